@@ -0,0 +1,204 @@
+package errwrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"github.com/rapidashorg/errwrap/internal/fmtargs"
+)
+
+var contextKeyLocale contextKey = "ctxk-locale"
+
+// InjectLocale injects the locale of the user into context, e.g. "id-ID". The
+// locale is used to localize the error messages, and is captured by error
+// wrappers created using the context.
+func InjectLocale(ctx context.Context, locale string) context.Context {
+	if ctx == nil {
+		return nil
+	}
+	return context.WithValue(ctx, contextKeyLocale, locale)
+}
+
+// LocaleFromContext returns the locale injected into context, or empty string
+// if there is none
+func LocaleFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	locale, _ := ctx.Value(contextKeyLocale).(string)
+	return locale
+}
+
+// Catalog contains localized message templates, keyed by locale and error code
+// string. Templates of masked errors replace the mask message, and templates
+// of unmasked errors replace the raw message, so they are formatted with the
+// error arguments, or filled with the params if the error is created from
+// message template. The catalog can be read and modified concurrently.
+type Catalog struct {
+	mu       sync.RWMutex
+	messages map[string]map[string]string
+}
+
+// NewCatalog creates empty catalog
+func NewCatalog() *Catalog {
+	return &Catalog{messages: make(map[string]map[string]string)}
+}
+
+// Add adds message template of the error code string in given locale
+func (c *Catalog) Add(locale string, codeString string, template string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.add(locale, codeString, template)
+}
+
+func (c *Catalog) add(locale string, codeString string, template string) {
+	locale = normalizeLocale(locale)
+	if c.messages[locale] == nil {
+		c.messages[locale] = make(map[string]string)
+	}
+	c.messages[locale][codeString] = template
+}
+
+// Message returns message template of the error code string in given locale.
+// If the locale doesn't have the template, the template of its base language
+// is used, e.g. "id" for "id-ID". Returns false if there is none.
+func (c *Catalog) Message(locale string, codeString string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	locale = normalizeLocale(locale)
+	for locale != "" {
+		if template, ok := c.messages[locale][codeString]; ok {
+			return template, true
+		}
+
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	return "", false
+}
+
+// LoadJSON loads message templates from JSON object keyed by locale, then by
+// error code string:
+//
+//	{"en": {"ErrNotFound": "Not found"}, "id": {"ErrNotFound": "Tidak ditemukan"}}
+func (c *Catalog) LoadJSON(data []byte) error {
+	var messages map[string]map[string]string
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return err
+	}
+
+	c.load(messages)
+	return nil
+}
+
+// LoadTOML loads message templates from TOML tables keyed by locale:
+//
+//	[en]
+//	ErrNotFound = "Not found"
+//
+//	[id]
+//	ErrNotFound = "Tidak ditemukan"
+func (c *Catalog) LoadTOML(data []byte) error {
+	var messages map[string]map[string]string
+	if err := toml.Unmarshal(data, &messages); err != nil {
+		return err
+	}
+
+	c.load(messages)
+	return nil
+}
+
+// LoadFS loads message templates from files matching the pattern, e.g. files
+// embedded using embed.FS. The file format is chosen from the file extension,
+// which is either ".json" or ".toml".
+func (c *Catalog) LoadFS(fsys fs.FS, pattern string) error {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+
+		switch ext := path.Ext(name); ext {
+		case ".json":
+			err = c.LoadJSON(data)
+		case ".toml":
+			err = c.LoadTOML(data)
+		default:
+			err = fmt.Errorf("unsupported catalog file extension %q", ext)
+		}
+		if err != nil {
+			return fmt.Errorf("errwrap: load catalog %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (c *Catalog) load(messages map[string]map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for locale, templates := range messages {
+		for codeString, template := range templates {
+			c.add(locale, codeString, template)
+		}
+	}
+}
+
+// MaskFormatter returns mask formatter using the mask message template in the
+// locale captured when the error wrapper is created, falling back to the raw
+// mask message. Use it as Config.MaskFormatter or ErrorDefinition mask
+// function to localize Error() of masked errors.
+func (c *Catalog) MaskFormatter() MaskFormatter {
+	return func(erw ErrorWrapper) string {
+		if e, ok := erw.(*errorWrapper); ok {
+			if template, ok := c.Message(e.locale, e.codeString); ok {
+				return template
+			}
+		}
+		return erw.RawMaskMessage()
+	}
+}
+
+// localize returns the localized message of the error wrapper, or false if
+// there is no template in the locale. The arguments are written for the
+// message format of the call site, so fmt templates whose number of arguments
+// doesn't match are not used.
+func (c *Catalog) localize(e *errorWrapper, locale string) (string, bool) {
+	template, ok := c.Message(locale, e.codeString)
+	if !ok {
+		return "", false
+	}
+
+	switch {
+	case e.isMasked:
+		return e.formatErrorMessage(template), true
+	case e.template != nil:
+		return e.formatErrorMessage(parseTemplate(template).render(e.Params())), true
+	}
+	if fmtargs.Count(template) != len(e.args) {
+		return "", false
+	}
+	return e.formatErrorMessage(fmt.Sprintf(template, e.redactor().renderArgs(e.args)...)), true
+}
+
+// normalizeLocale lowercases the locale and uses "-" as separator, so "id_ID"
+// and "id-id" are the same locale
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
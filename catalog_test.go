@@ -0,0 +1,149 @@
+package errwrap
+
+import (
+	"context"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func newTestCatalogFactory(t *testing.T) (*Factory, *Catalog) {
+	catalog := NewCatalog()
+	if err := catalog.LoadFS(os.DirFS("testdata/catalog"), "*"); err != nil {
+		t.Fatalf("Catalog.LoadFS() error = %v", err)
+	}
+
+	config := DefaultConfig()
+	config.Catalog = catalog
+	return NewFactory(config), catalog
+}
+
+func TestCatalog_Message(t *testing.T) {
+	_, catalog := newTestCatalogFactory(t)
+
+	tests := []struct {
+		name   string
+		locale string
+		want   string
+		wantOk bool
+	}{
+		{
+			name:   "success",
+			locale: "id",
+			want:   "Tidak ditemukan",
+			wantOk: true,
+		},
+		{
+			name:   "success base language",
+			locale: "id_ID",
+			want:   "Tidak ditemukan",
+			wantOk: true,
+		},
+		{
+			name:   "success case insensitive",
+			locale: "EN-us",
+			want:   "Not found",
+			wantOk: true,
+		},
+		{
+			name:   "not found",
+			locale: "fr",
+		},
+		{
+			name: "not found empty locale",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := catalog.Message(tt.locale, "ErrTestNotFound")
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("Catalog.Message() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestCatalog_LoadFS_unsupported(t *testing.T) {
+	fsys := fstest.MapFS{"en.yaml": &fstest.MapFile{Data: []byte("en: {}")}}
+	if err := NewCatalog().LoadFS(fsys, "*"); err == nil {
+		t.Errorf("Catalog.LoadFS() error = nil, want error")
+	}
+}
+
+func TestErrorWrapper_LocalizedError(t *testing.T) {
+	f, _ := newTestCatalogFactory(t)
+	edNotFound := f.NewError(100, "ErrTestNotFound", ErrorCategory(1)).MaskedMessage("Resource not found")
+	edBadRequest := f.NewError(101, "ErrTestBadRequest", ErrorCategory(1))
+	edOther := f.NewError(102, "ErrTestOther", ErrorCategory(1)).Masked()
+
+	ctxID := InjectLocale(context.Background(), "id-ID")
+
+	tests := []struct {
+		name string
+		erw  ErrorWrapper
+		ctx  context.Context
+		want string
+	}{
+		{
+			name: "success masked",
+			erw:  edNotFound.NewWithoutContext("User %d not found", 1),
+			ctx:  ctxID,
+			want: "Tidak ditemukan (100)",
+		},
+		{
+			name: "success unmasked with args",
+			erw:  edBadRequest.NewWithoutContext("Invalid name: %s", "foo"),
+			ctx:  ctxID,
+			want: "Permintaan tidak valid: foo (101)",
+		},
+		{
+			name: "success unmasked args count mismatch",
+			erw:  edBadRequest.NewWithoutContext("Invalid name %s: %s", "foo", "too long"),
+			ctx:  ctxID,
+			want: "Invalid name foo: too long (101)",
+		},
+		{
+			name: "success unmasked without args",
+			erw:  edBadRequest.NewWithoutContext("Invalid name"),
+			ctx:  ctxID,
+			want: "Invalid name (101)",
+		},
+		{
+			name: "success locale captured when created",
+			erw:  edNotFound.New(InjectLocale(context.Background(), "en"), "User %d not found", 1),
+			ctx:  context.Background(),
+			want: "Not found (100)",
+		},
+		{
+			name: "success fallback to mask message",
+			erw:  edNotFound.NewWithoutContext("User %d not found", 1),
+			ctx:  InjectLocale(context.Background(), "fr"),
+			want: "Resource not found (100)",
+		},
+		{
+			name: "success no translation",
+			erw:  edOther.NewWithoutContext("Unexpected"),
+			ctx:  ctxID,
+			want: DefaultMaskMessage + " (102)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.erw.LocalizedError(tt.ctx); got != tt.want {
+				t.Errorf("ErrorWrapper.LocalizedError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalog_MaskFormatter(t *testing.T) {
+	f, catalog := newTestCatalogFactory(t)
+	ed := f.NewError(100, "ErrTestNotFound", ErrorCategory(1)).MaskedFunction(catalog.MaskFormatter())
+
+	if got, want := ed.New(InjectLocale(context.Background(), "id"), "User not found").Error(), "Tidak ditemukan (100)"; got != want {
+		t.Errorf("Error() = %v, want %v", got, want)
+	}
+	if got, want := ed.NewWithoutContext("User not found").Error(), DefaultMaskMessage+" (100)"; got != want {
+		t.Errorf("Error() = %v, want %v", got, want)
+	}
+}
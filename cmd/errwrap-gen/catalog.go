@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Catalog is the catalog file describing error categories and error
+// definitions of a package
+type Catalog struct {
+	// Package is the generated Go package name, $GOPACKAGE set by go generate
+	// is used if this is empty
+	Package string `json:"package,omitempty" yaml:"package,omitempty"`
+
+	Categories []Category   `json:"categories" yaml:"categories"`
+	Errors     []Definition `json:"errors" yaml:"errors"`
+}
+
+// Category is an error category, generated as errwrap.ErrorCategory constant
+type Category struct {
+	// Name is the constant name, e.g. ErrCategoryBadRequest
+	Name string `json:"name" yaml:"name"`
+
+	// Value is the constant value, the previous category value plus one is
+	// used if this is nil, starting from zero
+	Value *int `json:"value,omitempty" yaml:"value,omitempty"`
+
+	Doc string `json:"doc,omitempty" yaml:"doc,omitempty"`
+}
+
+// Definition is an error definition, generated as variable created using
+// errwrap.NewError
+type Definition struct {
+	// Name is the variable name, e.g. ErrUserNotFound
+	Name string `json:"name" yaml:"name"`
+
+	Code int `json:"code" yaml:"code"`
+
+	// CodeString is the error code string, Name is used if this is empty
+	CodeString string `json:"code_string,omitempty" yaml:"code_string,omitempty"`
+
+	// Category is the name of the category in Catalog.Categories
+	Category string `json:"category" yaml:"category"`
+
+	Masked bool `json:"masked,omitempty" yaml:"masked,omitempty"`
+
+	// MaskMessage is the mask message, which masks the error definition even
+	// if Masked is false
+	MaskMessage string `json:"mask_message,omitempty" yaml:"mask_message,omitempty"`
+
+	// MessageFormat is the message format used by ErrorDefinition.NewArgs
+	MessageFormat string `json:"message_format,omitempty" yaml:"message_format,omitempty"`
+
+	Doc string `json:"doc,omitempty" yaml:"doc,omitempty"`
+}
+
+// parseCatalog parses the catalog file, the file format is chosen from the
+// file extension, which is either ".json", ".yaml", or ".yml"
+func parseCatalog(name string, data []byte) (*Catalog, error) {
+	var c Catalog
+	switch ext := filepath.Ext(name); ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&c); err != nil {
+			return nil, err
+		}
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&c); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported catalog file extension %q", ext)
+	}
+
+	c.normalize()
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// normalize fills the default values
+func (c *Catalog) normalize() {
+	next := 0
+	for i := range c.Categories {
+		cat := &c.Categories[i]
+		cat.Doc = strings.TrimSpace(cat.Doc)
+		if cat.Value == nil {
+			value := next
+			cat.Value = &value
+		}
+		next = *cat.Value + 1
+	}
+	for i := range c.Errors {
+		d := &c.Errors[i]
+		d.Doc = strings.TrimSpace(d.Doc)
+		if d.CodeString == "" {
+			d.CodeString = d.Name
+		}
+		if d.MaskMessage != "" {
+			d.Masked = true
+		}
+	}
+}
+
+// validate reports invalid names, unknown categories, and duplicate names,
+// codes, and code strings, which would make the generated definitions panic
+// when registered
+func (c *Catalog) validate() error {
+	names := make(map[string]string)
+	checkName := func(kind, name string) error {
+		if !token.IsIdentifier(name) {
+			return fmt.Errorf("%s name %q is not a valid Go identifier", kind, name)
+		}
+		if prev, ok := names[name]; ok {
+			return fmt.Errorf("duplicate %s name %q, also used by %s", kind, name, prev)
+		}
+		names[name] = kind
+		return nil
+	}
+
+	categories := make(map[string]bool)
+	for _, cat := range c.Categories {
+		if err := checkName("category", cat.Name); err != nil {
+			return err
+		}
+		categories[cat.Name] = true
+	}
+
+	codes := make(map[int]string)
+	codeStrings := make(map[string]string)
+	for _, d := range c.Errors {
+		if err := checkName("error", d.Name); err != nil {
+			return err
+		}
+		if !categories[d.Category] {
+			return fmt.Errorf("error %s has unknown category %q", d.Name, d.Category)
+		}
+		if prev, ok := codes[d.Code]; ok {
+			return fmt.Errorf("error %s has duplicate code %d, also used by %s", d.Name, d.Code, prev)
+		}
+		if prev, ok := codeStrings[d.CodeString]; ok {
+			return fmt.Errorf("error %s has duplicate code string %q, also used by %s", d.Name, d.CodeString, prev)
+		}
+		codes[d.Code] = d.Name
+		codeStrings[d.CodeString] = d.Name
+	}
+	return nil
+}
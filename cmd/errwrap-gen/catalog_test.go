@@ -0,0 +1,114 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCatalog(t *testing.T) {
+	yamlData, err := os.ReadFile("testdata/errors.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromYAML, err := parseCatalog("errors.yaml", yamlData)
+	if err != nil {
+		t.Fatalf("parseCatalog() YAML error = %v", err)
+	}
+
+	// the JSON reference is a valid catalog with the default values filled
+	jsonData, err := os.ReadFile("testdata/errors.json.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromJSON, err := parseCatalog("errors.json", jsonData)
+	if err != nil {
+		t.Fatalf("parseCatalog() JSON error = %v", err)
+	}
+
+	if !reflect.DeepEqual(fromYAML, fromJSON) {
+		t.Errorf("parseCatalog() YAML = %+v, JSON = %+v", fromYAML, fromJSON)
+	}
+
+	payment := fromYAML.Errors[3]
+	if payment.CodeString != "ErrPayment" || !payment.Masked {
+		t.Errorf("parseCatalog() defaults = %+v", payment)
+	}
+	if got := *fromYAML.Categories[2].Value; got != 11 {
+		t.Errorf("parseCatalog() category value = %d, want 11", got)
+	}
+}
+
+func TestParseCatalogError(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		data    string
+		wantErr string
+	}{
+		{
+			name:    "unsupported extension",
+			file:    "errors.toml",
+			wantErr: `unsupported catalog file extension ".toml"`,
+		},
+		{
+			name:    "unknown field",
+			file:    "errors.json",
+			data:    `{"errors": [{"name": "ErrA", "cod": 1}]}`,
+			wantErr: `unknown field "cod"`,
+		},
+		{
+			name: "invalid name",
+			file: "errors.yaml",
+			data: `
+categories: [{name: Cat}]
+errors: [{name: Err-A, code: 1, category: Cat}]`,
+			wantErr: `error name "Err-A" is not a valid Go identifier`,
+		},
+		{
+			name: "duplicate name",
+			file: "errors.yaml",
+			data: `
+categories: [{name: ErrA}]
+errors: [{name: ErrA, code: 1, category: ErrA}]`,
+			wantErr: `duplicate error name "ErrA", also used by category`,
+		},
+		{
+			name: "unknown category",
+			file: "errors.yaml",
+			data: `
+categories: [{name: Cat}]
+errors: [{name: ErrA, code: 1, category: Other}]`,
+			wantErr: `error ErrA has unknown category "Other"`,
+		},
+		{
+			name: "duplicate code",
+			file: "errors.yaml",
+			data: `
+categories: [{name: Cat}]
+errors:
+  - {name: ErrA, code: 1, category: Cat}
+  - {name: ErrB, code: 1, category: Cat}`,
+			wantErr: `error ErrB has duplicate code 1, also used by ErrA`,
+		},
+		{
+			name: "duplicate code string",
+			file: "errors.yaml",
+			data: `
+categories: [{name: Cat}]
+errors:
+  - {name: ErrA, code: 1, code_string: ErrB, category: Cat}
+  - {name: ErrB, code: 2, category: Cat}`,
+			wantErr: `error ErrB has duplicate code string "ErrB", also used by ErrA`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCatalog(tt.file, []byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseCatalog() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
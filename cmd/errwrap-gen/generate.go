@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+var goTemplate = template.Must(template.New("go").Funcs(template.FuncMap{
+	"comment": comment,
+	"quote":   strconv.Quote,
+}).Parse(`// Code generated by errwrap-gen from {{.Source}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import "github.com/rapidashorg/errwrap"
+{{if .Categories}}
+const (
+{{- range .Categories}}
+{{comment .Doc}}	{{.Name}} errwrap.ErrorCategory = {{.Value}}
+{{- end}}
+)
+{{end}}
+{{- if .Errors}}
+var (
+{{- range .Errors}}
+{{comment .Doc}}	{{.Name}} = errwrap.NewError({{.Code}}, {{quote .CodeString}}, {{.Category}})
+{{- if .MaskMessage}}.MaskedMessage({{quote .MaskMessage}}){{else if .Masked}}.Masked(){{end}}
+{{- if .MessageFormat}}.MessageFormat({{quote .MessageFormat}}){{end}}
+{{- end}}
+)
+{{end}}`))
+
+// generateGo generates the Go source declaring the categories and error
+// definitions of the catalog
+func generateGo(c *Catalog, source string) ([]byte, error) {
+	var buf bytes.Buffer
+	err := goTemplate.Execute(&buf, struct {
+		*Catalog
+		Source string
+	}{c, source})
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated source: %w", err)
+	}
+	return src, nil
+}
+
+// comment converts the doc into Go comment lines
+func comment(doc string) string {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, line := range strings.Split(doc, "\n") {
+		b.WriteString("\t// ")
+		b.WriteString(strings.TrimRight(line, " \t"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
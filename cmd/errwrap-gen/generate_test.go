@@ -0,0 +1,78 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "errors_gen.go")
+	md := filepath.Join(dir, "errors.md")
+	jsonOut := filepath.Join(dir, "errors.json")
+
+	if err := run([]string{"-in", "testdata/errors.yaml", "-out", out, "-md", md, "-json", jsonOut}); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	for got, golden := range map[string]string{
+		out:     "testdata/errors_gen.go.golden",
+		md:      "testdata/errors.md.golden",
+		jsonOut: "testdata/errors.json.golden",
+	} {
+		gotData, err := os.ReadFile(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantData, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(gotData) != string(wantData) {
+			t.Errorf("run() %s =\n%s\nwant\n%s", filepath.Base(got), gotData, wantData)
+		}
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), out, nil, parser.AllErrors); err != nil {
+		t.Errorf("run() generates invalid Go source: %v", err)
+	}
+}
+
+func TestRunPackage(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "errors.json")
+	if err := os.WriteFile(in, []byte(`{"categories": [{"name": "Cat"}], "errors": [{"name": "ErrA", "code": 1, "category": "Cat"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("GOPACKAGE", "")
+	if err := run([]string{"-in", in}); err == nil {
+		t.Errorf("run() without package name error = nil")
+	}
+
+	t.Setenv("GOPACKAGE", "gopkg")
+	if err := run([]string{"-in", in}); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, "errors_gen.go"), nil, parser.PackageClauseOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Name.Name != "gopkg" {
+		t.Errorf("run() package = %s, want gopkg", f.Name.Name)
+	}
+
+	if err := run([]string{"-in", in, "-package", "flagpkg"}); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	f, err = parser.ParseFile(token.NewFileSet(), filepath.Join(dir, "errors_gen.go"), nil, parser.PackageClauseOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Name.Name != "flagpkg" {
+		t.Errorf("run() package = %s, want flagpkg", f.Name.Name)
+	}
+}
@@ -0,0 +1,105 @@
+// Command errwrap-gen generates errwrap error definitions and category
+// constants from a YAML or JSON catalog file, along with markdown and JSON
+// references of the error definitions. It fails on duplicate codes, code
+// strings, and names. Use it with go generate:
+//
+//	//go:generate go run github.com/rapidashorg/errwrap/cmd/errwrap-gen -in errors.yaml -out errors_gen.go -md errors.md
+//
+// The catalog file looks like:
+//
+//	package: usererr
+//	categories:
+//	  - name: ErrCategoryBadRequest
+//	  - name: ErrCategoryInternalServerError
+//	errors:
+//	  - name: ErrUserNotFound
+//	    code: 1001
+//	    category: ErrCategoryBadRequest
+//	    message_format: "User %d not found"
+//	    doc: The requested user doesn't exist.
+//	  - name: ErrDatabase
+//	    code: 1002
+//	    category: ErrCategoryInternalServerError
+//	    mask_message: Please try again later.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "errwrap-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("errwrap-gen", flag.ContinueOnError)
+	in := fs.String("in", "", "catalog file, either .yaml, .yml, or .json")
+	out := fs.String("out", "", "generated Go file, defaults to the catalog file name with _gen.go suffix")
+	pkg := fs.String("package", "", "generated Go package name, overrides the catalog package")
+	md := fs.String("md", "", "generated markdown reference file, skipped if empty")
+	jsonOut := fs.String("json", "", "generated JSON reference file, skipped if empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *in == "" {
+		return errors.New("-in flag is required")
+	}
+
+	data, err := os.ReadFile(*in)
+	if err != nil {
+		return err
+	}
+	c, err := parseCatalog(*in, data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *in, err)
+	}
+
+	if *pkg != "" {
+		c.Package = *pkg
+	}
+	if c.Package == "" {
+		c.Package = os.Getenv("GOPACKAGE")
+	}
+	if c.Package == "" {
+		return errors.New("package name is not set, use -package flag or run using go generate")
+	}
+
+	if *out == "" {
+		*out = trimExt(*in) + "_gen.go"
+	}
+	src, err := generateGo(c, filepath.Base(*in))
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		return err
+	}
+
+	if *md != "" {
+		if err := os.WriteFile(*md, generateMarkdown(c), 0o644); err != nil {
+			return err
+		}
+	}
+
+	if *jsonOut != "" {
+		data, err := generateJSON(c)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*jsonOut, data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func trimExt(name string) string {
+	return name[:len(name)-len(filepath.Ext(name))]
+}
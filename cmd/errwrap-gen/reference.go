@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/rapidashorg/errwrap/internal/mdtable"
+)
+
+// generateMarkdown generates the markdown reference table of the error
+// definitions. The message is the mask message of masked definitions, as
+// returned to the clients, or the message format otherwise. Masked definitions
+// without mask message use the mask message of the factory settings.
+func generateMarkdown(c *Catalog) []byte {
+	table := mdtable.New(
+		mdtable.Column{Name: "Code", Right: true},
+		mdtable.Column{Name: "Code string"},
+		mdtable.Column{Name: "Category"},
+		mdtable.Column{Name: "Masked"},
+		mdtable.Column{Name: "Message"},
+		mdtable.Column{Name: "Description"},
+	)
+	for _, d := range c.Errors {
+		message := d.MessageFormat
+		if d.Masked {
+			message = d.MaskMessage
+		}
+		if d.Masked && strings.TrimSpace(message) == "" {
+			message = "_default mask message_"
+		}
+
+		table.Row(strconv.Itoa(d.Code), "`"+d.CodeString+"`", d.Category, strconv.FormatBool(d.Masked), message, d.Doc)
+	}
+	return []byte(table.String())
+}
+
+// generateJSON generates the JSON reference of the categories and error
+// definitions, with the default values filled
+func generateJSON(c *Catalog) ([]byte, error) {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
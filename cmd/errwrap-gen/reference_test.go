@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func TestGenerateMarkdown(t *testing.T) {
+	c := &Catalog{
+		Errors: []Definition{
+			{Code: 1, CodeString: "ErrEmpty", Category: "ErrCategoryBadRequest"},
+			{Code: 2, CodeString: "ErrNotFound", Category: "ErrCategoryNotFound", MessageFormat: "Not found", Doc: " multi\nline  text\n"},
+			{Code: 3, CodeString: "ErrPipe", Category: "ErrCategoryBadRequest", MessageFormat: "a | b"},
+			{Code: 4, CodeString: "ErrMasked", Category: "ErrCategoryInternal", Masked: true, MaskMessage: " \n"},
+		},
+	}
+
+	want := "| Code | Code string | Category | Masked | Message | Description |\n" +
+		"| ---: | --- | --- | --- | --- | --- |\n" +
+		"| 1 | `ErrEmpty` | ErrCategoryBadRequest | false |  |  |\n" +
+		"| 2 | `ErrNotFound` | ErrCategoryNotFound | false | Not found | multi line text |\n" +
+		"| 3 | `ErrPipe` | ErrCategoryBadRequest | false | a \\| b |  |\n" +
+		"| 4 | `ErrMasked` | ErrCategoryInternal | true | _default mask message_ |  |\n"
+	if got := string(generateMarkdown(c)); got != want {
+		t.Errorf("generateMarkdown() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,181 @@
+package errwrap
+
+import "sync/atomic"
+
+// Config defines the settings used to create error definitions and error
+// wrappers. Use DefaultConfig as the base when creating a new Config, as the
+// zero value disables some functionalities, e.g. stack trace capture.
+type Config struct {
+	// MaskMessage defines the mask message used when the error definition
+	// doesn't define its own mask message
+	MaskMessage string
+
+	// MaskFormatter defines the mask formatter function used when the error
+	// definition doesn't define its own mask formatter function
+	MaskFormatter MaskFormatter
+
+	// MessageFormatter defines the formatter function used when the error
+	// definition doesn't define its own message formatter function
+	MessageFormatter MessageFormatter
+
+	// PackagePrefix defines the project package prefix. This is used to trim
+	// stack trace to only include related project files. Set this to empty
+	// string to disable the trim functionality.
+	PackagePrefix string
+
+	// StackTraceMode defines the format of stack trace lines
+	StackTraceMode StackTraceMode
+
+	// StackTraceFilter defines the filter used to skip and shorten stack trace
+	// frames. Runtime and errwrap internal frames are always skipped.
+	StackTraceFilter StackTraceFilter
+
+	// StackTraceDepth defines the maximum number of frames captured in stack
+	// traces when the error definition doesn't set its own stack trace depth.
+	// Zero disables stack trace capture, and the depth is capped at 64 frames.
+	StackTraceDepth int
+
+	// DataMergePolicies defines the merge policy of error data keys injected by
+	// multiple layers. MergeShadow is used for keys without policy.
+	DataMergePolicies map[string]MergePolicy
+
+	// RedactKeys defines the error data key patterns whose values are redacted
+	// when the error data is rendered, e.g. "password" or "*token*". The
+	// patterns use path.Match syntax, and are matched case-insensitively.
+	RedactKeys []string
+
+	// RedactPlaceholder defines the placeholder replacing redacted error data
+	// and secret arguments
+	RedactPlaceholder string
+
+	// Catalog defines the localized message templates used by
+	// ErrorWrapper.LocalizedError. Messages are not localized if this is nil.
+	Catalog *Catalog
+
+	// JSONView defines the view used when the error wrapper is marshalled
+	// using json.Marshal
+	JSONView JSONView
+
+	// JSONPublicCodeString defines whether error code string is included in
+	// JSONViewPublic
+	JSONPublicCodeString bool
+
+	// Registry defines the registry where error definitions created by the
+	// factory are registered. A new registry is created if this is nil.
+	Registry *Registry
+
+	// DuplicateHandler defines the function called when the factory creates an
+	// error definition with duplicate code or code string
+	DuplicateHandler DuplicateHandler
+
+	// ArgsMismatchHandler defines the function called when the number of
+	// arguments passed to ErrorDefinition.NewArgs doesn't match the message
+	// format. The check is disabled if this is nil, e.g. set it to panic in
+	// tests.
+	ArgsMismatchHandler ArgsMismatchHandler
+}
+
+// DefaultConfig returns the default settings
+func DefaultConfig() Config {
+	return Config{
+		MaskMessage:       defaultMaskMessage,
+		MaskFormatter:     defaultMaskFormatter,
+		MessageFormatter:  defaultMessageFormatter,
+		StackTraceMode:    StackTraceModeFull,
+		StackTraceDepth:   maxStackDepth,
+		JSONView:          JSONViewPublic,
+		RedactPlaceholder: DefaultRedactPlaceholder,
+		DuplicateHandler:  DefaultDuplicateHandler,
+	}
+}
+
+// Factory creates error definitions using its own settings, so libraries in
+// the same binary can use different settings without stepping on each other.
+// The settings can be read and replaced concurrently.
+type Factory struct {
+	config atomic.Value // *Config
+
+	// deprecatedDefaults makes the deprecated package variables override the
+	// settings when they are set, only set for DefaultFactory
+	deprecatedDefaults bool
+}
+
+// NewFactory creates factory with given settings
+func NewFactory(config Config) *Factory {
+	f := &Factory{}
+	f.SetConfig(config)
+	return f
+}
+
+// newDeprecatedDefaultsFactory creates factory with default settings, which are
+// overridden by the deprecated package variables
+func newDeprecatedDefaultsFactory() *Factory {
+	f := &Factory{deprecatedDefaults: true}
+	f.SetConfig(DefaultConfig())
+	return f
+}
+
+// Config returns a copy of the factory settings
+func (f *Factory) Config() Config {
+	return *f.getConfig()
+}
+
+// SetConfig replaces the factory settings. Error definitions created by the
+// factory use the new settings for the next created error wrappers. If the
+// registry is nil, the current registry is kept.
+func (f *Factory) SetConfig(config Config) {
+	if config.MaskFormatter == nil {
+		config.MaskFormatter = defaultMaskFormatter
+	}
+	if config.MessageFormatter == nil {
+		config.MessageFormatter = defaultMessageFormatter
+	}
+	if config.RedactPlaceholder == "" {
+		config.RedactPlaceholder = DefaultRedactPlaceholder
+	}
+	if config.DuplicateHandler == nil {
+		config.DuplicateHandler = DefaultDuplicateHandler
+	}
+	if f.deprecatedDefaults {
+		config = *withDeprecatedDefaults(&config)
+	}
+	if config.Registry == nil {
+		if curr, ok := f.config.Load().(*Config); ok {
+			config.Registry = curr.Registry
+		} else {
+			config.Registry = NewRegistry()
+		}
+	}
+
+	f.config.Store(&config)
+}
+
+// Registry returns the registry where error definitions created by the factory
+// are registered
+func (f *Factory) Registry() *Registry {
+	return f.getConfig().Registry
+}
+
+// NewError creates simple error definition, and registers it to the factory
+// registry. If the code or code string has been registered, the duplicate
+// handler is called.
+func (f *Factory) NewError(code int, codeString string, category ErrorCategory) *ErrorDefinition {
+	config := f.getConfig()
+
+	ed := &ErrorDefinition{
+		code:       code,
+		codeString: codeString,
+		category:   category,
+		factory:    f,
+	}
+	if err := config.Registry.Register(ed); err != nil {
+		config.DuplicateHandler(err)
+	}
+	return ed
+}
+
+// getConfig returns the factory settings without copying, the returned
+// settings must not be modified
+func (f *Factory) getConfig() *Config {
+	return f.config.Load().(*Config)
+}
@@ -0,0 +1,193 @@
+package errwrap
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// setDefaultConfig modifies DefaultFactory settings, and restores them when
+// the test finishes
+func setDefaultConfig(t *testing.T, fn func(config *Config)) {
+	old := DefaultFactory.Config()
+	t.Cleanup(func() {
+		DefaultFactory.SetConfig(old)
+	})
+
+	config := old
+	fn(&config)
+	DefaultFactory.SetConfig(config)
+}
+
+func TestNewFactory(t *testing.T) {
+	registry := NewRegistry()
+
+	type args struct {
+		config Config
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantRegistry *Registry
+	}{
+		{
+			name: "success",
+			args: args{
+				config: Config{
+					MaskMessage: "Test masked error message",
+					Registry:    registry,
+				},
+			},
+			wantRegistry: registry,
+		},
+		{
+			name: "success zero value",
+			args: args{
+				config: Config{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewFactory(tt.args.config).Config()
+
+			if got.MaskMessage != tt.args.config.MaskMessage {
+				t.Errorf("NewFactory() MaskMessage = %v, want %v", got.MaskMessage, tt.args.config.MaskMessage)
+			}
+			if got.MaskFormatter == nil || got.MessageFormatter == nil || got.DuplicateHandler == nil {
+				t.Errorf("NewFactory() functions are not filled with default functions")
+			}
+			if got.Registry == nil {
+				t.Errorf("NewFactory() Registry is nil")
+			}
+			if tt.wantRegistry != nil && got.Registry != tt.wantRegistry {
+				t.Errorf("NewFactory() Registry = %p, want %p", got.Registry, tt.wantRegistry)
+			}
+		})
+	}
+}
+
+func TestFactory_SetConfig(t *testing.T) {
+	f := NewFactory(DefaultConfig())
+	registry := f.Registry()
+
+	config := f.Config()
+	config.MaskMessage = "Test masked error message"
+	config.Registry = nil
+	f.SetConfig(config)
+
+	if got := f.Config().MaskMessage; got != "Test masked error message" {
+		t.Errorf("Factory.Config() MaskMessage = %v, want %v", got, "Test masked error message")
+	}
+	if got := f.Registry(); got != registry {
+		t.Errorf("Factory.Registry() = %p, want %p", got, registry)
+	}
+}
+
+func TestFactory_NewError(t *testing.T) {
+	configA := DefaultConfig()
+	configA.MessageFormatter = func(msg string, erw ErrorWrapper) string {
+		return "A: " + msg
+	}
+
+	configB := DefaultConfig()
+	configB.MaskMessage = "Test masked error message"
+
+	tests := []struct {
+		name    string
+		factory *Factory
+		ed      func(f *Factory) *ErrorDefinition
+		want    string
+	}{
+		{
+			name:    "success message formatter",
+			factory: NewFactory(configA),
+			ed: func(f *Factory) *ErrorDefinition {
+				return f.NewError(100, "ErrTest", ErrorCategory(1))
+			},
+			want: "A: Test error message",
+		},
+		{
+			name:    "success mask message",
+			factory: NewFactory(configB),
+			ed: func(f *Factory) *ErrorDefinition {
+				return f.NewError(100, "ErrTest", ErrorCategory(1)).Masked()
+			},
+			want: "Test masked error message (100)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ed := tt.ed(tt.factory)
+
+			if got, ok := tt.factory.Registry().ByCode(100); !ok || got != ed {
+				t.Errorf("Factory.Registry().ByCode() = %v, want %v", got, ed)
+			}
+			if got := ed.NewWithoutContext("Test error message").Error(); got != tt.want {
+				t.Errorf("ErrorWrapper.Error() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeprecatedDefaults(t *testing.T) {
+	maskMessage, messageFormatter, stackTraceMode := DefaultMaskMessage, DefaultMessageFormatter, DefaultStackTraceMode
+	config := DefaultFactory.Config()
+	t.Cleanup(func() {
+		DefaultMaskMessage, DefaultMessageFormatter, DefaultStackTraceMode = maskMessage, messageFormatter, stackTraceMode
+		DefaultFactory.SetConfig(config)
+	})
+
+	DefaultMaskMessage = "Test masked error message"
+	DefaultMessageFormatter = func(msg string, erw ErrorWrapper) string {
+		return "deprecated: " + msg
+	}
+	DefaultStackTraceMode = StackTraceModeFuncOnly
+
+	erw := NewError(100, "ErrTest", ErrorCategory(1)).Masked().NewWithoutContext("Test error message")
+	if got, want := erw.Error(), defaultMaskMessage+" (100)"; got != want {
+		t.Errorf("ErrorWrapper.Error() before sync = %v, want %v", got, want)
+	}
+
+	SyncDeprecatedDefaults()
+	erw = NewError(100, "ErrTest", ErrorCategory(1)).Masked().NewWithoutContext("Test error message")
+	if got, want := erw.Error(), "deprecated: Test masked error message"; got != want {
+		t.Errorf("ErrorWrapper.Error() = %v, want %v", got, want)
+	}
+	if got := erw.StackTrace(); len(got) == 0 || strings.Contains(got[0], ".go:") {
+		t.Errorf("ErrorWrapper.StackTrace() = %v, want function only lines", got)
+	}
+
+	f := NewFactory(DefaultConfig())
+	erw = f.NewError(100, "ErrTest", ErrorCategory(1)).Masked().NewWithoutContext("Test error message")
+	if got, want := erw.Error(), defaultMaskMessage+" (100)"; got != want {
+		t.Errorf("Factory ErrorWrapper.Error() = %v, want %v", got, want)
+	}
+}
+
+func TestFactory_concurrent(t *testing.T) {
+	f := NewFactory(DefaultConfig())
+	ed := f.NewError(100, "ErrTest", ErrorCategory(1)).Masked()
+
+	messages := []string{"Test masked error message 1", "Test masked error message 2"}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			config := f.Config()
+			config.MaskMessage = messages[i%2]
+			f.SetConfig(config)
+		}(i)
+		go func() {
+			defer wg.Done()
+			got := ed.NewWithoutContext("Test error message").RawMaskMessage()
+			if got != DefaultMaskMessage && !reflect.DeepEqual(got, messages[0]) && !reflect.DeepEqual(got, messages[1]) {
+				t.Errorf("ErrorWrapper.RawMaskMessage() = %v", got)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -1,13 +1,43 @@
 package errwrap
 
-import "context"
+import (
+	"context"
+	"runtime"
+)
 
 // ErrorData contains additional data for debugging purpose
 type ErrorData map[string]interface{}
 type errorDataWrapper struct {
 	data   ErrorData
 	parent *errorDataWrapper
+	pc     uintptr // caller injecting the data, zero if not recorded
+}
+
+// ErrorDataLayer is the error data injected by a single InjectErrorData call
+type ErrorDataLayer struct {
+	Data ErrorData
+
+	// Caller is the location injecting the data, only recorded when the data
+	// is injected using InjectErrorDataWithCaller
+	Caller *Frame
 }
+
+// MergePolicy defines how values of the same error data key injected by
+// multiple layers are merged into ErrorWrapper.Data
+type MergePolicy int
+
+const (
+	// MergeShadow keeps the value injected last, shadowing the values
+	// injected before
+	MergeShadow MergePolicy = iota
+
+	// MergeKeepFirst keeps the value injected first
+	MergeKeepFirst
+
+	// MergeCollect collects all values into []interface{}, in injection order
+	MergeCollect
+)
+
 type contextKey string
 
 var (
@@ -18,6 +48,18 @@ var (
 // context has been injected and injected again with same key but different
 // value, the old value will be overwritten with the new value.
 func InjectErrorData(ctx context.Context, data ErrorData) context.Context {
+	return injectErrorData(ctx, data, 0)
+}
+
+// InjectErrorDataWithCaller is the same as InjectErrorData, but records the
+// caller location, returned by ErrorDataLayers and DataLayers
+func InjectErrorDataWithCaller(ctx context.Context, data ErrorData) context.Context {
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+	return injectErrorData(ctx, data, pcs[0])
+}
+
+func injectErrorData(ctx context.Context, data ErrorData, pc uintptr) context.Context {
 	if ctx == nil {
 		return nil
 	}
@@ -31,6 +73,7 @@ func InjectErrorData(ctx context.Context, data ErrorData) context.Context {
 	curr := &errorDataWrapper{
 		data:   data,
 		parent: parent,
+		pc:     pc,
 	}
 
 	ctx = context.WithValue(ctx, contextKeyErrorData, curr)
@@ -55,32 +98,69 @@ func getErrorDataWrapper(ctx context.Context) *errorDataWrapper {
 	return errDataWrapper
 }
 
-// getErrorData returns ErrorData from given context
+// ErrorDataLayers returns the error data injected into context, a layer per
+// InjectErrorData call, from the last injected layer
+func ErrorDataLayers(ctx context.Context) []ErrorDataLayer {
+	return getErrorDataWrapper(ctx).layers()
+}
+
+// getErrorData returns ErrorData from given context, values injected last
+// shadow the values injected before
 func getErrorData(ctx context.Context) ErrorData {
-	if ctx == nil {
+	return getErrorDataWrapper(ctx).merge(nil)
+}
+
+// merge merges the data of all layers using the merge policy of each key,
+// MergeShadow is used for keys without policy
+func (w *errorDataWrapper) merge(policies map[string]MergePolicy) ErrorData {
+	if w == nil {
 		return nil
 	}
 
-	var errData ErrorData
-
-	errDataWrapper := getErrorDataWrapper(ctx)
-
-	for {
-		if errDataWrapper == nil {
-			return errData
+	errData := make(ErrorData)
+	var collected map[string][]interface{}
+
+	for ; w != nil; w = w.parent {
+		for k, v := range w.data {
+			switch policies[k] {
+			case MergeKeepFirst:
+				errData[k] = v
+
+			case MergeCollect:
+				if collected == nil {
+					collected = make(map[string][]interface{})
+				}
+				collected[k] = append(collected[k], v)
+
+			default:
+				if _, exists := errData[k]; !exists {
+					errData[k] = v
+				}
+			}
 		}
+	}
 
-		if errData == nil {
-			errData = make(ErrorData)
+	for k, values := range collected {
+		// values are collected from the last injected layer
+		for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+			values[i], values[j] = values[j], values[i]
 		}
+		errData[k] = values
+	}
+	return errData
+}
 
-		for k, v := range errDataWrapper.data {
-			if _, exists := errData[k]; exists {
-				continue
-			}
-			errData[k] = v
+// layers returns the layers from the last injected layer
+func (w *errorDataWrapper) layers() []ErrorDataLayer {
+	var layers []ErrorDataLayer
+	for ; w != nil; w = w.parent {
+		layer := ErrorDataLayer{Data: w.data}
+		if w.pc != 0 {
+			frame, _ := runtime.CallersFrames([]uintptr{w.pc}).Next()
+			f := newFrame(frame)
+			layer.Caller = &f
 		}
-
-		errDataWrapper = errDataWrapper.parent
+		layers = append(layers, layer)
 	}
+	return layers
 }
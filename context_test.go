@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -238,3 +239,64 @@ func Test_getErrorData(t *testing.T) {
 		})
 	}
 }
+
+func Test_errorDataWrapper_merge(t *testing.T) {
+	ctx := InjectErrorData(context.Background(), ErrorData{"id": "order-1", "user_id": 1})
+	ctx = InjectErrorData(ctx, ErrorData{"id": "item-1"})
+	ctx = InjectErrorData(ctx, ErrorData{"id": "sku-1"})
+
+	tests := []struct {
+		name     string
+		policies map[string]MergePolicy
+		want     ErrorData
+	}{
+		{
+			name: "success shadow",
+			want: ErrorData{"id": "sku-1", "user_id": 1},
+		},
+		{
+			name:     "success keep first",
+			policies: map[string]MergePolicy{"id": MergeKeepFirst},
+			want:     ErrorData{"id": "order-1", "user_id": 1},
+		},
+		{
+			name:     "success collect",
+			policies: map[string]MergePolicy{"id": MergeCollect, "user_id": MergeCollect},
+			want: ErrorData{
+				"id":      []interface{}{"order-1", "item-1", "sku-1"},
+				"user_id": []interface{}{1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getErrorDataWrapper(ctx).merge(tt.policies); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("errorDataWrapper.merge() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorDataLayers(t *testing.T) {
+	ctx := InjectErrorData(context.Background(), ErrorData{"id": "order-1"})
+	ctx = InjectErrorDataWithCaller(ctx, ErrorData{"id": "item-1"})
+
+	got := ErrorDataLayers(ctx)
+	if len(got) != 2 {
+		t.Fatalf("ErrorDataLayers() = %v, want 2 layers", got)
+	}
+
+	if !reflect.DeepEqual(got[0].Data, ErrorData{"id": "item-1"}) || got[0].Caller == nil {
+		t.Errorf("ErrorDataLayers()[0] = %+v", got[0])
+	} else if got[0].Caller.Function != "TestErrorDataLayers" || !strings.HasSuffix(got[0].Caller.File, "context_test.go") {
+		t.Errorf("ErrorDataLayers()[0].Caller = %+v", got[0].Caller)
+	}
+
+	if !reflect.DeepEqual(got[1], ErrorDataLayer{Data: ErrorData{"id": "order-1"}}) {
+		t.Errorf("ErrorDataLayers()[1] = %+v", got[1])
+	}
+
+	if got := ErrorDataLayers(context.Background()); got != nil {
+		t.Errorf("ErrorDataLayers() = %v, want nil", got)
+	}
+}
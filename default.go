@@ -1,13 +1,17 @@
 package errwrap
 
-import "fmt"
+import (
+	"fmt"
+	"reflect"
+)
 
-type stackTraceMode int
+// StackTraceMode defines the format of stack trace lines
+type StackTraceMode int
 
 const (
 	// StackTraceModeFull will gather full data of the stack traces (filename,
 	// line number, and function name)
-	StackTraceModeFull stackTraceMode = iota
+	StackTraceModeFull StackTraceMode = iota
 
 	// StackTraceModeLineOnly will gather filename and line number only for data
 	// of the stack traces
@@ -18,29 +22,118 @@ const (
 	StackTraceModeFuncOnly
 )
 
+// defaultMaskMessage is the mask message of DefaultConfig
+const defaultMaskMessage = "Sorry, there are internal server error occured, please try again later."
+
+// defaultMaskFormatter is the mask formatter function of DefaultConfig, which
+// returns the raw mask message
+func defaultMaskFormatter(erw ErrorWrapper) string {
+	return erw.RawMaskMessage()
+}
+
+// defaultMessageFormatter is the message formatter function of DefaultConfig,
+// which appends the error code to plain or mask message
+func defaultMessageFormatter(msg string, e ErrorWrapper) string {
+	return fmt.Sprintf("%s (%d)", msg, e.Code())
+}
+
 var (
 	// DefaultMaskMessage defines the default mask message used when mask
 	// message is not defined
-	DefaultMaskMessage string = "Sorry, there are internal server error occured, please try again later."
+	//
+	// Deprecated: Set Config.MaskMessage using DefaultFactory.SetConfig
+	// instead. Changing this variable only affects DefaultFactory after
+	// SyncDeprecatedDefaults is called.
+	DefaultMaskMessage = defaultMaskMessage
 
 	// DefaultMaskFormatter defines the mask formatter function used to format
 	// mask message when the function is not defined
-	DefaultMaskFormatter MaskFormatter = func(erw ErrorWrapper) string {
-		return erw.RawMaskMessage()
-	}
+	//
+	// Deprecated: Set Config.MaskFormatter using DefaultFactory.SetConfig
+	// instead. Changing this variable only affects DefaultFactory after
+	// SyncDeprecatedDefaults is called.
+	DefaultMaskFormatter MaskFormatter = defaultMaskFormatter
 
 	// DefaultMessageFormatter defines the formatter function used to format
 	// formatted plain or mask message when the function is not defined.
 	// In default, this function will set error code to plain or mask message.
-	DefaultMessageFormatter MessageFormatter = func(msg string, e ErrorWrapper) string {
-		return fmt.Sprintf("%s (%d)", msg, e.Code())
-	}
+	//
+	// Deprecated: Set Config.MessageFormatter using DefaultFactory.SetConfig
+	// instead. Changing this variable only affects DefaultFactory after
+	// SyncDeprecatedDefaults is called.
+	DefaultMessageFormatter MessageFormatter = defaultMessageFormatter
 
 	// DefaultPackagePrefix defines the project package prefix. This variable is
 	// used to trim stack trace to only include related project files. Set this
 	// to empty string if you want to disable the trim functionality
+	//
+	// Deprecated: Set Config.PackagePrefix using DefaultFactory.SetConfig
+	// instead. Changing this variable only affects DefaultFactory after
+	// SyncDeprecatedDefaults is called.
 	DefaultPackagePrefix string
 
 	// DefaultStackTraceMode defines the mode used to gather stack traces data.
+	//
+	// Deprecated: Set Config.StackTraceMode using DefaultFactory.SetConfig
+	// instead. Changing this variable only affects DefaultFactory after
+	// SyncDeprecatedDefaults is called.
 	DefaultStackTraceMode = StackTraceModeFull
 )
+
+// SyncDeprecatedDefaults applies the deprecated package variables, e.g.
+// DefaultPackagePrefix, to DefaultFactory settings. Call this after changing
+// the variables, as DefaultFactory only reads them when its settings are set.
+//
+// Deprecated: Set Config using DefaultFactory.SetConfig instead.
+func SyncDeprecatedDefaults() {
+	DefaultFactory.SetConfig(DefaultFactory.Config())
+}
+
+// withDeprecatedDefaults returns the settings overridden by the deprecated
+// package variables which have been changed from their initial values, so code
+// assigning them keeps working with DefaultFactory
+func withDeprecatedDefaults(config *Config) *Config {
+	changed := DefaultMaskMessage != defaultMaskMessage ||
+		DefaultPackagePrefix != "" ||
+		DefaultStackTraceMode != StackTraceModeFull ||
+		!sameFunc(DefaultMaskFormatter, defaultMaskFormatter) ||
+		!sameFunc(DefaultMessageFormatter, defaultMessageFormatter)
+	if !changed {
+		return config
+	}
+
+	c := *config
+	if DefaultMaskMessage != defaultMaskMessage {
+		c.MaskMessage = DefaultMaskMessage
+	}
+	if DefaultPackagePrefix != "" {
+		c.PackagePrefix = DefaultPackagePrefix
+	}
+	if DefaultStackTraceMode != StackTraceModeFull {
+		c.StackTraceMode = DefaultStackTraceMode
+	}
+	if DefaultMaskFormatter != nil && !sameFunc(DefaultMaskFormatter, defaultMaskFormatter) {
+		c.MaskFormatter = DefaultMaskFormatter
+	}
+	if DefaultMessageFormatter != nil && !sameFunc(DefaultMessageFormatter, defaultMessageFormatter) {
+		c.MessageFormatter = DefaultMessageFormatter
+	}
+	return &c
+}
+
+// sameFunc checks whether both functions have the same code pointer
+func sameFunc(a, b interface{}) bool {
+	return reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+}
+
+// DefaultDuplicateHandler is the default function called when an error
+// definition is created with duplicate code or code string. This function
+// panics, so duplicates are caught when the program starts.
+func DefaultDuplicateHandler(err error) {
+	panic(err)
+}
+
+// DefaultFactory is the factory used by package level functions, e.g. NewError.
+// Its settings are overridden by the deprecated package variables, e.g.
+// DefaultPackagePrefix, when SetConfig or SyncDeprecatedDefaults is called.
+var DefaultFactory = newDeprecatedDefaultsFactory()
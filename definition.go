@@ -23,37 +23,86 @@ type ErrorDefinition struct {
 	maskMessage   *string           // error message mask
 	maskFormatter *MaskFormatter    // mask formatter function
 	category      ErrorCategory     // error category
+	stackDepth    *int              // maximum number of stack trace frames
+	template      *messageTemplate  // message template with named placeholders
+	messageFormat *string           // default message format used by NewArgs
+	factory       *Factory          // factory which creates the definition
 }
 
-// NewError creates simple error definition
+// NewError creates simple error definition using DefaultFactory
 func NewError(code int, codeString string, category ErrorCategory) *ErrorDefinition {
-	return &ErrorDefinition{
-		code:          code,
-		codeString:    codeString,
-		category:      category,
-		formatter:     &DefaultMessageFormatter,
-		maskMessage:   &DefaultMaskMessage,
-		maskFormatter: &DefaultMaskFormatter,
+	return DefaultFactory.NewError(code, codeString, category)
+}
+
+// Code returns the error code
+func (ed *ErrorDefinition) Code() int {
+	return ed.code
+}
+
+// CodeString returns the error code in string
+func (ed *ErrorDefinition) CodeString() string {
+	return ed.codeString
+}
+
+// Category returns the error category
+func (ed *ErrorDefinition) Category() ErrorCategory {
+	return ed.category
+}
+
+// IsMasked reports whether the error wrappers created from the error
+// definition are masked
+func (ed *ErrorDefinition) IsMasked() bool {
+	return ed.isMasked
+}
+
+// PublicMessage returns the message returned to clients by the error wrappers
+// created from the error definition, before the arguments are filled, e.g. for
+// documentation. This is the mask message if the definition is masked, or the
+// message template or message format otherwise. The message formatter and mask
+// formatter are not called, so the message is computed from the definition
+// alone. It returns empty string if the message is only passed when creating
+// the error wrappers, or computed by the MaskedFunction function.
+func (ed *ErrorDefinition) PublicMessage() string {
+	switch {
+	case ed.isMasked && ed.maskFormatter != nil:
+		return ""
+	case ed.isMasked && ed.maskMessage != nil:
+		return *ed.maskMessage
+	case ed.isMasked:
+		return ed.getFactory().getConfig().MaskMessage
+	case ed.template != nil:
+		return ed.template.raw
+	case ed.messageFormat != nil:
+		return *ed.messageFormat
 	}
+	return ""
+}
+
+// Error returns the error code string. This function exists so the error
+// definition implements error interface, and can be used as the target of
+// errors.Is, e.g. errors.Is(err, ErrBadRequest).
+func (ed *ErrorDefinition) Error() string {
+	return ed.codeString
 }
 
 // Masked masks this error definition, makes produced errorWrapper message
-// masked with maskMessage. The mask message used by this function is the
-// default one.
+// masked with maskMessage. The mask message and mask formatter function used
+// by this function are the ones from the factory settings.
 func (ed *ErrorDefinition) Masked() *ErrorDefinition {
 	ed.isMasked = true
-	ed.maskMessage = &DefaultMaskMessage
-	ed.maskFormatter = &DefaultMaskFormatter
+	ed.maskMessage = nil
+	ed.maskFormatter = nil
 	return ed
 }
 
 // MaskedMessage masks this error definition, makes produced errorWrapper
 // message masked with maskMessage. The mask message used by this function is
-// passed as arguments, and the mask formatter function used is the default one.
+// passed as arguments, and the mask formatter function used is the one from
+// the factory settings.
 func (ed *ErrorDefinition) MaskedMessage(maskMessage string) *ErrorDefinition {
 	ed.isMasked = true
 	ed.maskMessage = &maskMessage
-	ed.maskFormatter = &DefaultMaskFormatter
+	ed.maskFormatter = nil
 	return ed
 }
 
@@ -72,17 +121,84 @@ func (ed *ErrorDefinition) MessageFormatter(fn MessageFormatter) *ErrorDefinitio
 	return ed
 }
 
+// WithoutStackTrace disables stack trace capture for this error definition.
+// This is useful for expected errors, e.g. validation errors, where the stack
+// trace is never read.
+func (ed *ErrorDefinition) WithoutStackTrace() *ErrorDefinition {
+	return ed.StackTraceDepth(0)
+}
+
+// StackTraceCallerOnly makes this error definition capture only the frame
+// where the error wrapper is created
+func (ed *ErrorDefinition) StackTraceCallerOnly() *ErrorDefinition {
+	return ed.StackTraceDepth(1)
+}
+
+// StackTraceFull makes this error definition capture full stack trace,
+// regardless of the stack trace depth in the factory settings. The stack trace
+// is still capped at 64 frames, the maximum depth.
+func (ed *ErrorDefinition) StackTraceFull() *ErrorDefinition {
+	return ed.StackTraceDepth(maxStackDepth)
+}
+
+// StackTraceDepth sets the maximum number of frames captured in stack trace for
+// this error definition, overriding the stack trace depth in the factory
+// settings. Zero or negative depth disables stack trace capture. Depth is
+// capped at 64 frames, and counts the frames kept after internal and filtered
+// frames are dropped.
+func (ed *ErrorDefinition) StackTraceDepth(depth int) *ErrorDefinition {
+	ed.stackDepth = &depth
+	return ed
+}
+
+// stackTraceDepth returns the maximum number of stack trace frames of this
+// error definition
+func (ed *ErrorDefinition) stackTraceDepth() int {
+	if ed.stackDepth != nil {
+		return *ed.stackDepth
+	}
+	return ed.getFactory().getConfig().StackTraceDepth
+}
+
+// getFactory returns the factory which creates this error definition, or
+// DefaultFactory if the error definition is not created by a factory
+func (ed *ErrorDefinition) getFactory() *Factory {
+	if ed.factory != nil {
+		return ed.factory
+	}
+	return DefaultFactory
+}
+
 // NewWithoutContext creates new ErrorWrapper based on error definition without
 // passed context
 func (ed *ErrorDefinition) NewWithoutContext(rawMessage string, args ...interface{}) ErrorWrapper {
 	erw := newErrorWrapper(context.Background(), ed, rawMessage, args...)
-	erw.fillStackTrace(1)
+	erw.fillStackTrace(1, ed.stackTraceDepth())
 	return erw
 }
 
 // New creates new ErrorWrapper based on error definition
 func (ed *ErrorDefinition) New(ctx context.Context, rawMessage string, args ...interface{}) ErrorWrapper {
 	erw := newErrorWrapper(ctx, ed, rawMessage, args...)
-	erw.fillStackTrace(1)
+	erw.fillStackTrace(1, ed.stackTraceDepth())
+	return erw
+}
+
+// WrapWithoutContext creates new ErrorWrapper based on error definition that
+// wraps the cause error, without passed context
+func (ed *ErrorDefinition) WrapWithoutContext(cause error, rawMessage string, args ...interface{}) ErrorWrapper {
+	erw := newErrorWrapper(context.Background(), ed, rawMessage, args...)
+	erw.cause = cause
+	erw.fillStackTrace(1, ed.stackTraceDepth())
+	return erw
+}
+
+// Wrap creates new ErrorWrapper based on error definition that wraps the cause
+// error. The cause can be retrieved using errors.Unwrap, errors.Is and
+// errors.As from the standard library.
+func (ed *ErrorDefinition) Wrap(ctx context.Context, cause error, rawMessage string, args ...interface{}) ErrorWrapper {
+	erw := newErrorWrapper(ctx, ed, rawMessage, args...)
+	erw.cause = cause
+	erw.fillStackTrace(1, ed.stackTraceDepth())
 	return erw
 }
@@ -2,7 +2,9 @@ package errwrap
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"reflect"
 	"testing"
 )
@@ -30,9 +32,7 @@ func TestNewError(t *testing.T) {
 				codeString: "ErrTest",
 				category:   ErrorCategory(1),
 
-				formatter:     &DefaultMessageFormatter,
-				maskMessage:   &DefaultMaskMessage,
-				maskFormatter: &DefaultMaskFormatter,
+				factory: DefaultFactory,
 			},
 		},
 	}
@@ -47,6 +47,90 @@ func TestNewError(t *testing.T) {
 	}
 }
 
+func TestErrorDefinition_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		ed   *ErrorDefinition
+		want string
+	}{
+		{
+			name: "success",
+			ed:   NewError(100, "ErrTest", ErrorCategory(1)),
+			want: "ErrTest",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ed.Error(); got != tt.want {
+				t.Errorf("ErrorDefinition.Error() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorDefinition_PublicMessage(t *testing.T) {
+	f := NewFactory(DefaultConfig())
+	tests := []struct {
+		name       string
+		ed         *ErrorDefinition
+		wantMasked bool
+		want       string
+	}{
+		{
+			name: "without message",
+			ed:   f.NewError(100, "ErrTest", ErrorCategory(1)),
+			want: "",
+		},
+		{
+			name: "message format",
+			ed:   f.NewError(101, "ErrFormat", ErrorCategory(1)).MessageFormat("User %d not found"),
+			want: "User %d not found",
+		},
+		{
+			name: "message template",
+			ed:   f.NewError(102, "ErrTemplate", ErrorCategory(1)).MessageTemplate("User {user_id} not found"),
+			want: "User {user_id} not found",
+		},
+		{
+			name:       "masked",
+			ed:         f.NewError(103, "ErrMasked", ErrorCategory(1)).MessageFormat("query %s failed").Masked(),
+			wantMasked: true,
+			want:       DefaultMaskMessage,
+		},
+		{
+			name:       "masked message",
+			ed:         f.NewError(104, "ErrMaskedMessage", ErrorCategory(1)).MaskedMessage("Try again later"),
+			wantMasked: true,
+			want:       "Try again later",
+		},
+		{
+			name: "masked function",
+			ed: f.NewError(105, "ErrMaskedFunction", ErrorCategory(1)).MaskedFunction(func(erw ErrorWrapper) string {
+				return fmt.Sprintf("Try again in %d minutes", erw.Args()[0])
+			}),
+			wantMasked: true,
+			want:       "",
+		},
+		{
+			name: "message formatter not called",
+			ed: f.NewError(106, "ErrFormatter", ErrorCategory(1)).MessageFormat("User %d not found").MessageFormatter(func(msg string, erw ErrorWrapper) string {
+				return fmt.Sprintf("%s (user %d)", msg, erw.Args()[0])
+			}),
+			want: "User %d not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ed.IsMasked(); got != tt.wantMasked {
+				t.Errorf("ErrorDefinition.IsMasked() = %v, want %v", got, tt.wantMasked)
+			}
+			if got := tt.ed.PublicMessage(); got != tt.want {
+				t.Errorf("ErrorDefinition.PublicMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestErrorDefinition_Masked(t *testing.T) {
 	type fields struct {
 		code          int
@@ -73,10 +157,8 @@ func TestErrorDefinition_Masked(t *testing.T) {
 				codeString: "ErrTest",
 				category:   ErrorCategory(1),
 
-				isMasked:      true,
-				maskMessage:   &DefaultMaskMessage,
-				formatter:     &DefaultMessageFormatter,
-				maskFormatter: &DefaultMaskFormatter,
+				isMasked: true,
+				factory:  DefaultFactory,
 			},
 		},
 	}
@@ -126,10 +208,9 @@ func TestErrorDefinition_MaskedMessage(t *testing.T) {
 				codeString: "ErrTest",
 				category:   ErrorCategory(1),
 
-				isMasked:      true,
-				maskMessage:   stringPtr("Test masked error message"),
-				formatter:     &DefaultMessageFormatter,
-				maskFormatter: &DefaultMaskFormatter,
+				isMasked:    true,
+				maskMessage: stringPtr("Test masked error message"),
+				factory:     DefaultFactory,
 			},
 		},
 	}
@@ -181,9 +262,7 @@ func TestErrorDefinition_MaskedFunction(t *testing.T) {
 				codeString: "ErrTest",
 				category:   ErrorCategory(1),
 
-				isMasked:    true,
-				formatter:   &DefaultMessageFormatter,
-				maskMessage: &DefaultMaskMessage,
+				isMasked: true,
 				maskFormatter: maskFormatterPtr(func(erw ErrorWrapper) string {
 					return "test " + erw.RawMaskMessage()
 				}),
@@ -245,8 +324,6 @@ func TestErrorDefinition_MessageFormatter(t *testing.T) {
 				formatter: messageFormatterPtr(func(msg string, erw ErrorWrapper) string {
 					return fmt.Sprintf("test %s (%d)", msg, erw.Code())
 				}),
-				maskMessage:   &DefaultMaskMessage,
-				maskFormatter: &DefaultMaskFormatter,
 			},
 		},
 	}
@@ -308,6 +385,7 @@ func TestErrorDefinition_NewWithoutContext(t *testing.T) {
 				formatter:     nil,
 				maskMessage:   DefaultMaskMessage,
 				maskFormatter: nil,
+				factory:       DefaultFactory,
 			},
 		},
 	}
@@ -317,7 +395,9 @@ func TestErrorDefinition_NewWithoutContext(t *testing.T) {
 
 			got := ed.NewWithoutContext(tt.args.rawMessage, tt.args.args...)
 			if g, ok := got.(*errorWrapper); ok {
+				g.stack = nil
 				g.stackTrace = nil
+				g.dataLayers = nil
 				g.maskFormatter = nil
 				g.formatter = nil
 			}
@@ -376,6 +456,7 @@ func TestErrorDefinition_New(t *testing.T) {
 				formatter:     nil,
 				maskMessage:   DefaultMaskMessage,
 				maskFormatter: nil,
+				factory:       DefaultFactory,
 			},
 		},
 	}
@@ -385,7 +466,9 @@ func TestErrorDefinition_New(t *testing.T) {
 
 			got := ed.New(tt.args.ctx, tt.args.rawMessage, tt.args.args...)
 			if g, ok := got.(*errorWrapper); ok {
+				g.stack = nil
 				g.stackTrace = nil
+				g.dataLayers = nil
 				g.maskFormatter = nil
 				g.formatter = nil
 			}
@@ -396,3 +479,111 @@ func TestErrorDefinition_New(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorDefinition_Wrap(t *testing.T) {
+	errSentinel := errors.New("sentinel error")
+
+	type fields struct {
+		code       int
+		codeString string
+		category   ErrorCategory
+	}
+	type args struct {
+		ctx        context.Context
+		cause      error
+		rawMessage string
+		args       []interface{}
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		want   ErrorWrapper
+	}{
+		{
+			name: "success",
+			fields: fields{
+				code:       100,
+				codeString: "ErrTest",
+				category:   ErrorCategory(1),
+			},
+			args: args{
+				ctx: InjectErrorData(context.Background(), ErrorData{
+					"foo": "bar",
+				}),
+				cause:      errSentinel,
+				args:       []interface{}{"Foo"},
+				rawMessage: "Test error message",
+			},
+			want: &errorWrapper{
+				code:       100,
+				codeString: "ErrTest",
+				message:    "Test error message",
+				category:   ErrorCategory(1),
+				args:       []interface{}{"Foo"},
+				data: ErrorData{
+					"foo": "bar",
+				},
+				cause: errSentinel,
+
+				formatter:     nil,
+				maskMessage:   DefaultMaskMessage,
+				maskFormatter: nil,
+				factory:       DefaultFactory,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ed := NewError(tt.fields.code, tt.fields.codeString, tt.fields.category)
+
+			got := ed.Wrap(tt.args.ctx, tt.args.cause, tt.args.rawMessage, tt.args.args...)
+			if g, ok := got.(*errorWrapper); ok {
+				g.stack = nil
+				g.stackTrace = nil
+				g.dataLayers = nil
+				g.maskFormatter = nil
+				g.formatter = nil
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ErrorDefinition.Wrap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorDefinition_Wrap_errorsChain(t *testing.T) {
+	errSentinel := errors.New("sentinel error")
+	ed := NewError(100, "ErrTest", ErrorCategory(1))
+
+	_, pathErr := os.Open("/non/existent/file")
+
+	tests := []struct {
+		name  string
+		cause error
+	}{
+		{
+			name:  "success sentinel error",
+			cause: errSentinel,
+		},
+		{
+			name:  "success typed error",
+			cause: pathErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error = ed.WrapWithoutContext(tt.cause, "Test error message")
+
+			if !errors.Is(err, tt.cause) {
+				t.Errorf("errors.Is() = false, want true")
+			}
+
+			var target *os.PathError
+			if errors.As(tt.cause, &target) && !errors.As(err, &target) {
+				t.Errorf("errors.As() = false, want true")
+			}
+		})
+	}
+}
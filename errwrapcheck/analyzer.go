@@ -0,0 +1,417 @@
+// Package errwrapcheck defines an analyzer reporting misuse of errwrap:
+//
+//   - fmt format and argument count mismatch in ErrorDefinition.New, Wrap,
+//     and their WithoutContext variants
+//   - argument count mismatch in ErrorDefinition.NewArgs against the message
+//     format of the error definition variable
+//   - NewWithoutContext variants called in functions having context.Context
+//     parameter, which drops the error data injected into the context
+//   - ErrorWrapper compared using == or !=, instead of errors.Is
+//   - duplicate literal error codes or code strings passed to package level
+//     NewError calls of the same factory
+//
+// Only the number of arguments is checked against the format, mismatches
+// between the format verbs and the argument types are not reported. NewArgs
+// calls are checked only when the error definition is a package level
+// variable whose MessageFormat is a constant, and the method is called on the
+// variable directly, not through a method value.
+//
+// The analyzer can be used with go vet -vettool, singlechecker, or
+// multichecker.
+package errwrapcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"github.com/rapidashorg/errwrap/errwrapcheck/internal/fmtargs"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const errwrapPath = "github.com/rapidashorg/errwrap"
+
+// Analyzer reports misuse of errwrap
+var Analyzer = &analysis.Analyzer{
+	Name:      "errwrapcheck",
+	Doc:       "check for misuse of errwrap error definitions and error wrappers",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	Run:       run,
+	FactTypes: []analysis.Fact{new(messageFormatFact)},
+}
+
+// messageFormatFact is the message format of an error definition variable,
+// set using ErrorDefinition.MessageFormat
+type messageFormatFact struct {
+	Format string
+}
+
+func (*messageFormatFact) AFact() {}
+
+func (f *messageFormatFact) String() string {
+	return fmt.Sprintf("messageFormat(%q)", f.Format)
+}
+
+// formatArgIndex is the index of the format argument of ErrorDefinition
+// methods taking fmt format
+var formatArgIndex = map[string]int{
+	"New":                1,
+	"NewWithoutContext":  0,
+	"Wrap":               2,
+	"WrapWithoutContext": 1,
+}
+
+// withContext is the method to be used instead of the WithoutContext methods
+var withContext = map[string]string{
+	"NewWithoutContext":       "New",
+	"WrapWithoutContext":      "Wrap",
+	"NewArgsWithoutContext":   "NewArgs",
+	"NewParamsWithoutContext": "NewParams",
+}
+
+// newArgsIndex is the index of the first argument of NewArgs methods
+var newArgsIndex = map[string]int{
+	"NewArgs":               1,
+	"NewArgsWithoutContext": 0,
+}
+
+// registry is the literal error codes and code strings passed to NewError
+// calls of a factory
+type registry struct {
+	codes       map[int64]token.Pos
+	codeStrings map[string]token.Pos
+}
+
+type checker struct {
+	pass *analysis.Pass
+
+	// registries is keyed by the factory variable, nil for DefaultFactory
+	registries map[types.Object]*registry
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	c := &checker{
+		pass:       pass,
+		registries: make(map[types.Object]*registry),
+	}
+	c.exportMessageFormats()
+
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodeFilter := []ast.Node{
+		(*ast.CallExpr)(nil),
+		(*ast.BinaryExpr)(nil),
+	}
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			c.checkCall(n, stack)
+		case *ast.BinaryExpr:
+			c.checkComparison(n)
+		}
+		return true
+	})
+	return nil, nil
+}
+
+// exportMessageFormats exports the message format of package level error
+// definition variables, so NewArgs calls can be checked in other packages
+func (c *checker) exportMessageFormats() {
+	for _, file := range c.pass.Files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+
+			for _, spec := range gen.Specs {
+				spec := spec.(*ast.ValueSpec)
+				if len(spec.Names) != len(spec.Values) {
+					continue
+				}
+				for i, name := range spec.Names {
+					format, ok := c.messageFormat(spec.Values[i])
+					if !ok {
+						continue
+					}
+					if obj := c.pass.TypesInfo.Defs[name]; obj != nil {
+						c.pass.ExportObjectFact(obj, &messageFormatFact{Format: format})
+					}
+				}
+			}
+		}
+	}
+}
+
+// messageFormat finds constant ErrorDefinition.MessageFormat call in the
+// chained builder calls
+func (c *checker) messageFormat(expr ast.Expr) (string, bool) {
+	for {
+		call, ok := ast.Unparen(expr).(*ast.CallExpr)
+		if !ok {
+			return "", false
+		}
+		sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+		if !ok {
+			return "", false
+		}
+
+		if recv, name := c.callee(call); recv == "ErrorDefinition" && name == "MessageFormat" && len(call.Args) == 1 {
+			if format, ok := c.constantString(call.Args[0]); ok {
+				return format, true
+			}
+		}
+		expr = sel.X
+	}
+}
+
+func (c *checker) checkCall(call *ast.CallExpr, stack []ast.Node) {
+	recv, name := c.callee(call)
+	switch recv {
+	case "ErrorDefinition":
+		if idx, ok := formatArgIndex[name]; ok {
+			c.checkFormat(call, name, idx)
+		}
+		if idx, ok := newArgsIndex[name]; ok {
+			c.checkNewArgs(call, name, idx)
+		}
+		if replacement, ok := withContext[name]; ok && hasContextParam(c.pass.TypesInfo, stack) {
+			c.pass.Reportf(call.Pos(), "%s called in function with context.Context parameter, use %s to keep the error data", name, replacement)
+		}
+
+	case "", "Factory":
+		if name == "NewError" && !inFunction(stack) {
+			c.checkNewError(call)
+		}
+	}
+}
+
+// callee returns the receiver type name and function name if the call calls
+// errwrap function or method. The receiver type name is empty for functions.
+func (c *checker) callee(call *ast.CallExpr) (recv string, name string) {
+	fn, ok := typeutil.Callee(c.pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != errwrapPath {
+		return "-", ""
+	}
+
+	sig := fn.Type().(*types.Signature)
+	if sig.Recv() == nil {
+		return "", fn.Name()
+	}
+
+	t := sig.Recv().Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		return named.Obj().Name(), fn.Name()
+	}
+	return "-", ""
+}
+
+// checkFormat checks the number of arguments against the constant format
+func (c *checker) checkFormat(call *ast.CallExpr, name string, idx int) {
+	if call.Ellipsis.IsValid() || len(call.Args) <= idx {
+		return
+	}
+	format, ok := c.constantString(call.Args[idx])
+	if !ok {
+		return
+	}
+
+	if want, got := fmtargs.Count(format), len(call.Args)-idx-1; want != got {
+		c.pass.Reportf(call.Pos(), "%s format %q wants %d args, but call has %d args", name, format, want, got)
+	}
+}
+
+// checkNewArgs checks the number of arguments against the message format of
+// the error definition variable
+func (c *checker) checkNewArgs(call *ast.CallExpr, name string, idx int) {
+	if call.Ellipsis.IsValid() {
+		return
+	}
+
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+
+	var ident *ast.Ident
+	switch x := ast.Unparen(sel.X).(type) {
+	case *ast.Ident:
+		ident = x
+	case *ast.SelectorExpr:
+		ident = x.Sel
+	default:
+		return
+	}
+
+	obj := c.pass.TypesInfo.Uses[ident]
+	var fact messageFormatFact
+	if obj == nil || !c.pass.ImportObjectFact(obj, &fact) {
+		return
+	}
+
+	if want, got := fmtargs.Count(fact.Format), len(call.Args)-idx; want != got {
+		c.pass.Reportf(call.Pos(), "%s of %s with message format %q wants %d args, but call has %d args", name, ident.Name, fact.Format, want, got)
+	}
+}
+
+// checkNewError reports literal error codes and code strings used by another
+// package level NewError call of the same factory in the package
+func (c *checker) checkNewError(call *ast.CallExpr) {
+	if len(call.Args) < 2 {
+		return
+	}
+	factory, ok := c.factory(call)
+	if !ok {
+		return
+	}
+	r, ok := c.registries[factory]
+	if !ok {
+		r = &registry{
+			codes:       make(map[int64]token.Pos),
+			codeStrings: make(map[string]token.Pos),
+		}
+		c.registries[factory] = r
+	}
+
+	if tv, ok := c.pass.TypesInfo.Types[call.Args[0]]; ok && tv.Value != nil && tv.Value.Kind() == constant.Int {
+		if code, exact := constant.Int64Val(tv.Value); exact {
+			if pos, ok := r.codes[code]; ok {
+				c.pass.Reportf(call.Args[0].Pos(), "duplicate error code %d, also used at %s", code, c.pass.Fset.Position(pos))
+			} else {
+				r.codes[code] = call.Args[0].Pos()
+			}
+		}
+	}
+
+	if codeString, ok := c.constantString(call.Args[1]); ok {
+		if pos, ok := r.codeStrings[codeString]; ok {
+			c.pass.Reportf(call.Args[1].Pos(), "duplicate error code string %q, also used at %s", codeString, c.pass.Fset.Position(pos))
+		} else {
+			r.codeStrings[codeString] = call.Args[1].Pos()
+		}
+	}
+}
+
+// factory returns the package level factory variable whose NewError method is
+// called, or nil for package level NewError and DefaultFactory. The factory is
+// unknown if the receiver isn't a package level variable.
+func (c *checker) factory(call *ast.CallExpr) (types.Object, bool) {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return nil, false
+	}
+
+	var ident *ast.Ident
+	switch x := ast.Unparen(sel.X).(type) {
+	case *ast.Ident:
+		ident = x
+	case *ast.SelectorExpr:
+		ident = x.Sel
+	default:
+		return nil, false
+	}
+
+	switch obj := c.pass.TypesInfo.Uses[ident].(type) {
+	case *types.PkgName:
+		// package level NewError
+		return nil, obj.Imported().Path() == errwrapPath
+	case *types.Var:
+		if obj.Pkg() == nil || obj.Parent() != obj.Pkg().Scope() {
+			return nil, false
+		}
+		if obj.Pkg().Path() == errwrapPath && obj.Name() == "DefaultFactory" {
+			return nil, true
+		}
+		return obj, true
+	}
+	return nil, false
+}
+
+// checkComparison reports ErrorWrapper compared using == or !=, except
+// comparison with nil
+func (c *checker) checkComparison(expr *ast.BinaryExpr) {
+	if expr.Op != token.EQL && expr.Op != token.NEQ {
+		return
+	}
+
+	info := c.pass.TypesInfo
+	if info.Types[expr.X].IsNil() || info.Types[expr.Y].IsNil() {
+		return
+	}
+	if isErrorWrapper(info.TypeOf(expr.X)) || isErrorWrapper(info.TypeOf(expr.Y)) {
+		c.pass.Reportf(expr.OpPos, "ErrorWrapper compared using %s, use errors.Is or ErrorWrapper.Is instead", expr.Op)
+	}
+}
+
+func (c *checker) constantString(expr ast.Expr) (string, bool) {
+	tv, ok := c.pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// isErrorWrapper reports whether the type is errwrap.ErrorWrapper
+func isErrorWrapper(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == errwrapPath && obj.Name() == "ErrorWrapper"
+}
+
+// inFunction reports whether the node is inside a function, error definitions
+// created in functions commonly use their own factory, e.g. in tests
+func inFunction(stack []ast.Node) bool {
+	for _, n := range stack {
+		switch n.(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			return true
+		}
+	}
+	return false
+}
+
+// hasContextParam reports whether any of the enclosing functions has
+// context.Context parameter
+func hasContextParam(info *types.Info, stack []ast.Node) bool {
+	for i := len(stack) - 1; i >= 0; i-- {
+		var fnType *ast.FuncType
+		switch fn := stack[i].(type) {
+		case *ast.FuncDecl:
+			fnType = fn.Type
+		case *ast.FuncLit:
+			fnType = fn.Type
+		default:
+			continue
+		}
+
+		for _, field := range fnType.Params.List {
+			if isContext(info.TypeOf(field.Type)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isContext(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "context" && obj.Name() == "Context"
+}
@@ -0,0 +1,14 @@
+// Command errwrapcheck reports misuse of errwrap, see package errwrapcheck.
+// It can be run directly, or using go vet:
+//
+//	go vet -vettool=$(which errwrapcheck) ./...
+package main
+
+import (
+	"github.com/rapidashorg/errwrap/errwrapcheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(errwrapcheck.Analyzer)
+}
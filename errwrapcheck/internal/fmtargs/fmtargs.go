@@ -0,0 +1,90 @@
+// Package fmtargs counts the arguments consumed by fmt format strings, the same
+// way as go vet printf check. This is a copy of errwrap internal/fmtargs, so
+// the analyzer module doesn't depend on errwrap.
+package fmtargs
+
+import (
+	"strconv"
+	"unicode/utf8"
+)
+
+// Count returns the number of arguments consumed by the fmt.Sprintf format,
+// including '*' width and precision, and explicit argument indexes
+func Count(format string) int {
+	argNum, maxArgNum := 0, 0
+	consume := func() {
+		argNum++
+		if argNum > maxArgNum {
+			maxArgNum = argNum
+		}
+	}
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+
+		// flags
+		for i < len(format) && (format[i] == '+' || format[i] == '-' || format[i] == '#' || format[i] == ' ' || format[i] == '0') {
+			i++
+		}
+
+		i = parseArgIndex(format, i, &argNum)
+
+		// width
+		if i < len(format) && format[i] == '*' {
+			consume()
+			i++
+		} else {
+			for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+				i++
+			}
+		}
+
+		// precision
+		if i < len(format) && format[i] == '.' {
+			i++
+			i = parseArgIndex(format, i, &argNum)
+			if i < len(format) && format[i] == '*' {
+				consume()
+				i++
+			} else {
+				for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+					i++
+				}
+			}
+		}
+
+		i = parseArgIndex(format, i, &argNum)
+
+		if i >= len(format) {
+			break
+		}
+		if format[i] == '%' {
+			continue
+		}
+
+		consume()
+		_, size := utf8.DecodeRuneInString(format[i:])
+		i += size - 1
+	}
+	return maxArgNum
+}
+
+// parseArgIndex parses explicit argument index, e.g. "[2]", setting argNum to
+// the argument before the index. Returns the position after the index.
+func parseArgIndex(format string, i int, argNum *int) int {
+	if i >= len(format) || format[i] != '[' {
+		return i
+	}
+	for j := i + 1; j < len(format); j++ {
+		if format[j] == ']' {
+			if n, err := strconv.Atoi(format[i+1 : j]); err == nil && n > 0 {
+				*argNum = n - 1
+			}
+			return j + 1
+		}
+	}
+	return i
+}
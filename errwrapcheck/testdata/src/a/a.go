@@ -0,0 +1,75 @@
+package a
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rapidashorg/errwrap"
+)
+
+var (
+	ErrNotFound = errwrap.NewError(1000, "not_found", 1).MessageFormat("user %s not found in %s") // want ErrNotFound:`messageFormat\("user %s not found in %s"\)`
+	ErrInvalid  = errwrap.NewError(1001, "invalid", 1).MaskedMessage("invalid")
+	ErrConflict = errwrap.NewError(1000, "conflict", 1) // want `duplicate error code 1000, also used at .*a.go:11:33`
+	ErrTimeout  = errwrap.NewError(1002, "invalid", 1)  // want `duplicate error code string "invalid", also used at .*a.go:12:39`
+)
+
+var factory = errwrap.NewFactory(errwrap.DefaultConfig())
+
+var (
+	ErrFactory          = factory.NewError(1001, "factory", 1)
+	ErrFactoryDuplicate = factory.NewError(1001, "factory_duplicate", 1)      // want `duplicate error code 1001, also used at .*a.go:20:41`
+	ErrDefaultFactory   = errwrap.DefaultFactory.NewError(1003, "invalid", 1) // want `duplicate error code string "invalid", also used at .*a.go:12:39`
+)
+
+func format(ctx context.Context, cause error, args []interface{}) {
+	ErrInvalid.New(ctx, "user %s not found", "alice")
+	ErrInvalid.New(ctx, "user %s not found")             // want `New format "user %s not found" wants 1 args, but call has 0 args`
+	ErrInvalid.New(ctx, "user %[2]s not found", "alice") // want `New format "user %\[2\]s not found" wants 2 args, but call has 1 args`
+	ErrInvalid.Wrap(ctx, cause, "100%% broken %d", 1, 2) // want `Wrap format "100%% broken %d" wants 1 args, but call has 2 args`
+	ErrInvalid.New(ctx, "user %s not found", args...)
+}
+
+func newArgs(ctx context.Context, args []interface{}) {
+	ErrNotFound.NewArgs(ctx, "alice", "db")
+	ErrNotFound.NewArgs(ctx, "alice") // want `NewArgs of ErrNotFound with message format "user %s not found in %s" wants 2 args, but call has 1 args`
+	ErrNotFound.NewArgs(ctx, args...)
+	(ErrNotFound.NewArgs)(ctx, "alice") // want `NewArgs of ErrNotFound with message format "user %s not found in %s" wants 2 args, but call has 1 args`
+	fn := ErrNotFound.NewArgs
+	fn(ctx, "alice")
+	ErrInvalid.NewArgs(ctx, "alice")
+}
+
+func withoutContext(cause error) {
+	ErrInvalid.NewWithoutContext("invalid %d", 1)
+	ErrInvalid.WrapWithoutContext(cause, "invalid")
+	ErrNotFound.NewArgsWithoutContext("alice", "db")
+	ErrInvalid.NewParamsWithoutContext(nil)
+}
+
+func withContext(ctx context.Context, cause error) {
+	ErrInvalid.NewWithoutContext("invalid")              // want `NewWithoutContext called in function with context.Context parameter, use New to keep the error data`
+	ErrInvalid.WrapWithoutContext(cause, "invalid")      // want `WrapWithoutContext called in function with context.Context parameter, use Wrap to keep the error data`
+	ErrNotFound.NewArgsWithoutContext("alice")           // want `NewArgsWithoutContext called in function with context.Context parameter, use NewArgs to keep the error data` `NewArgsWithoutContext of ErrNotFound with message format "user %s not found in %s" wants 2 args, but call has 1 args`
+	ErrInvalid.NewParamsWithoutContext(errwrap.Params{}) // want `NewParamsWithoutContext called in function with context.Context parameter, use NewParams to keep the error data`
+	func() { ErrInvalid.NewWithoutContext("invalid") }() // want `NewWithoutContext called in function with context.Context parameter, use New to keep the error data`
+}
+
+func compare(ctx context.Context, err error) bool {
+	erw := ErrInvalid.New(ctx, "invalid")
+	if erw == nil || nil != erw {
+		return false
+	}
+	if erw == err { // want `ErrorWrapper compared using ==, use errors.Is or ErrorWrapper.Is instead`
+		return true
+	}
+	if err != erw { // want `ErrorWrapper compared using !=, use errors.Is or ErrorWrapper.Is instead`
+		return true
+	}
+	return errors.Is(err, erw)
+}
+
+func isolated() {
+	f := errwrap.NewFactory(errwrap.DefaultConfig())
+	f.NewError(1000, "not_found", 1)
+}
@@ -0,0 +1,12 @@
+package b
+
+import (
+	"context"
+
+	"a"
+)
+
+func newArgs(ctx context.Context) {
+	a.ErrNotFound.NewArgs(ctx, "alice", "db")
+	a.ErrNotFound.NewArgs(ctx, "alice", "db", "users") // want `NewArgs of ErrNotFound with message format "user %s not found in %s" wants 2 args, but call has 3 args`
+}
@@ -0,0 +1,73 @@
+// Package errwrap is a stub of errwrap API checked by errwrapcheck, the
+// declared signatures must match the real package
+package errwrap
+
+import "context"
+
+type ErrorCategory int
+
+type Params map[string]interface{}
+
+type ErrorWrapper interface {
+	error
+	Is(err error) bool
+}
+
+type ErrorDefinition struct{}
+
+func NewError(code int, codeString string, category ErrorCategory) *ErrorDefinition {
+	return &ErrorDefinition{}
+}
+
+type Config struct{}
+
+func DefaultConfig() Config {
+	return Config{}
+}
+
+type Factory struct{}
+
+func NewFactory(config Config) *Factory {
+	return &Factory{}
+}
+
+var DefaultFactory = NewFactory(DefaultConfig())
+
+func (f *Factory) NewError(code int, codeString string, category ErrorCategory) *ErrorDefinition {
+	return &ErrorDefinition{}
+}
+
+func (ed *ErrorDefinition) MaskedMessage(maskMessage string) *ErrorDefinition { return ed }
+func (ed *ErrorDefinition) MessageFormat(format string) *ErrorDefinition      { return ed }
+
+func (ed *ErrorDefinition) New(ctx context.Context, rawMessage string, args ...interface{}) ErrorWrapper {
+	return nil
+}
+
+func (ed *ErrorDefinition) NewWithoutContext(rawMessage string, args ...interface{}) ErrorWrapper {
+	return nil
+}
+
+func (ed *ErrorDefinition) Wrap(ctx context.Context, cause error, rawMessage string, args ...interface{}) ErrorWrapper {
+	return nil
+}
+
+func (ed *ErrorDefinition) WrapWithoutContext(cause error, rawMessage string, args ...interface{}) ErrorWrapper {
+	return nil
+}
+
+func (ed *ErrorDefinition) NewArgs(ctx context.Context, args ...interface{}) ErrorWrapper {
+	return nil
+}
+
+func (ed *ErrorDefinition) NewArgsWithoutContext(args ...interface{}) ErrorWrapper {
+	return nil
+}
+
+func (ed *ErrorDefinition) NewParams(ctx context.Context, params Params) ErrorWrapper {
+	return nil
+}
+
+func (ed *ErrorDefinition) NewParamsWithoutContext(params Params) ErrorWrapper {
+	return nil
+}
@@ -0,0 +1,10 @@
+package errwrap
+
+// MaxStackDepth exposes maxStackDepth to the external tests
+const MaxStackDepth = maxStackDepth
+
+// StackTraceDepthOf exposes the stack trace depth of the error definition to
+// the external tests
+func StackTraceDepthOf(ed *ErrorDefinition) int {
+	return ed.stackTraceDepth()
+}
@@ -0,0 +1,41 @@
+package errwrap
+
+// FieldViolation describes an invalid field of a request, e.g. for form
+// validation errors. Unlike ErrorData, field violations are meant to be sent
+// to the client, so the message must be safe to be shown to the user.
+type FieldViolation struct {
+	// Field is the path of the field, e.g. "items[0].name"
+	Field string `json:"field"`
+
+	// Code is the violation code, e.g. "required"
+	Code string `json:"code"`
+
+	// Message is the user-safe violation message
+	Message string `json:"message"`
+}
+
+// WithFields returns copy of the error wrapper with given field violations
+// appended. Error wrappers not created by this package are returned as is.
+func WithFields(erw ErrorWrapper, fields ...FieldViolation) ErrorWrapper {
+	e, ok := erw.(*errorWrapper)
+	if !ok || len(fields) == 0 {
+		return erw
+	}
+
+	copied := *e
+	copied.fields = make([]FieldViolation, 0, len(e.fields)+len(fields))
+	copied.fields = append(copied.fields, e.fields...)
+	copied.fields = append(copied.fields, fields...)
+	return &copied
+}
+
+// Fields returns the field violations of all error wrappers. These are the
+// field violations written by httperr and grpcerr for the multi error, e.g.
+// for batch validation errors.
+func (m *MultiError) Fields() []FieldViolation {
+	var fields []FieldViolation
+	for _, erw := range m.wrappers() {
+		fields = append(fields, erw.Fields()...)
+	}
+	return fields
+}
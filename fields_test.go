@@ -0,0 +1,72 @@
+package errwrap
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWithFields(t *testing.T) {
+	ed := NewFactory(DefaultConfig()).NewError(100, "ErrBadRequest", ErrorCategory(1))
+	fieldName := FieldViolation{Field: "name", Code: "required", Message: "Name is required"}
+	fieldEmail := FieldViolation{Field: "email", Code: "invalid", Message: "Email is invalid"}
+
+	erw := ed.NewWithoutContext("Invalid body")
+	got := WithFields(WithFields(erw, fieldName), fieldEmail)
+
+	if erw.Fields() != nil {
+		t.Errorf("WithFields() modifies the error wrapper, Fields() = %v", erw.Fields())
+	}
+	if want := []FieldViolation{fieldName, fieldEmail}; !reflect.DeepEqual(got.Fields(), want) {
+		t.Errorf("WithFields() Fields() = %v, want %v", got.Fields(), want)
+	}
+	if !got.Is(ed) || got.ActualError() != "Invalid body (100)" {
+		t.Errorf("WithFields() = %v", got)
+	}
+	if got := WithFields(erw); got != erw {
+		t.Errorf("WithFields() without fields = %p, want %p", got, erw)
+	}
+
+	if verbose := fmt.Sprintf("%+v", got); !strings.Contains(verbose, "\nfields:\n\tname: Name is required (required)\n\temail: Email is invalid (invalid)") {
+		t.Errorf("WithFields() %%+v = %v", verbose)
+	}
+
+	m := NewMultiError(got, ed.NewWithoutContext("Invalid query"), WithFields(ed.NewWithoutContext("Invalid header"), fieldName))
+	if want := []FieldViolation{fieldName, fieldEmail, fieldName}; !reflect.DeepEqual(m.Fields(), want) {
+		t.Errorf("MultiError.Fields() = %v, want %v", m.Fields(), want)
+	}
+}
+
+func TestWithFields_json(t *testing.T) {
+	f := NewFactory(DefaultConfig())
+	ed := f.NewError(100, "ErrBadRequest", ErrorCategory(1))
+	fields := []FieldViolation{{Field: "items[0].name", Code: "required", Message: "Name is required"}}
+	erw := WithFields(ed.NewWithoutContext("Invalid body"), fields...)
+
+	for _, view := range []JSONView{JSONViewPublic, JSONViewDebug} {
+		data, err := f.MarshalError(erw, view)
+		if err != nil {
+			t.Fatalf("Factory.MarshalError() error = %v", err)
+		}
+		if view == JSONViewPublic {
+			want := `{"message":"Invalid body (100)","code":100,"fields":[{"field":"items[0].name","code":"required","message":"Name is required"}]}`
+			if string(data) != want {
+				t.Errorf("Factory.MarshalError() = %s, want %s", data, want)
+			}
+		}
+
+		got, err := f.UnmarshalError(data, view)
+		if err != nil {
+			t.Fatalf("Factory.UnmarshalError() error = %v", err)
+		}
+		if !reflect.DeepEqual(got.Fields(), fields) {
+			t.Errorf("Factory.UnmarshalError() view %v Fields() = %v, want %v", view, got.Fields(), fields)
+		}
+	}
+
+	if data, _ := json.Marshal(ed.NewWithoutContext("Invalid body")); strings.Contains(string(data), "fields") {
+		t.Errorf("json.Marshal() without fields = %s", data)
+	}
+}
@@ -0,0 +1,68 @@
+package errwrap
+
+import (
+	"fmt"
+	"io"
+)
+
+// Format implements fmt.Formatter. %s and %v print the error message, which is
+// masked if the error is masked, %q prints the quoted error message, %+v
+// prints the actual error message along with code string, category, error
+// data, stack trace, and the cause error, and %#v prints a Go-syntax
+// representation of the error wrapper.
+func (e *errorWrapper) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		switch {
+		case s.Flag('+'):
+			e.formatVerbose(s)
+		case s.Flag('#'):
+			e.formatGoSyntax(s)
+		default:
+			io.WriteString(s, e.Error())
+		}
+
+	case 's':
+		io.WriteString(s, e.Error())
+
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+
+	default:
+		fmt.Fprintf(s, "%%!%c(%s)", verb, e.Error())
+	}
+}
+
+// formatVerbose writes the error wrapper details for debugging purpose
+func (e *errorWrapper) formatVerbose(w io.Writer) {
+	io.WriteString(w, e.ActualError())
+	fmt.Fprintf(w, "\ncode string: %s", e.codeString)
+	fmt.Fprintf(w, "\ncategory: %d", e.category)
+	if params := e.Params(); len(params) > 0 {
+		fmt.Fprintf(w, "\nparams: %v", params)
+	}
+	if data := e.Data(); len(data) > 0 {
+		fmt.Fprintf(w, "\ndata: %v", data)
+	}
+	if len(e.fields) > 0 {
+		io.WriteString(w, "\nfields:")
+		for _, field := range e.fields {
+			fmt.Fprintf(w, "\n\t%s: %s (%s)", field.Field, field.Message, field.Code)
+		}
+	}
+	if stackTrace := e.StackTrace(); len(stackTrace) > 0 {
+		io.WriteString(w, "\nstack trace:")
+		for _, line := range stackTrace {
+			fmt.Fprintf(w, "\n\t%s", line)
+		}
+	}
+	if e.cause != nil {
+		fmt.Fprintf(w, "\ncaused by: %+v", e.cause)
+	}
+}
+
+// formatGoSyntax writes the error wrapper in Go-syntax-like format
+func (e *errorWrapper) formatGoSyntax(w io.Writer) {
+	fmt.Fprintf(w, "&errwrap.errorWrapper{code:%d, codeString:%q, category:%d, isMasked:%t, message:%q, maskMessage:%q, args:%#v, stackTrace:%#v, data:%#v, cause:%#v}",
+		e.code, e.codeString, e.category, e.isMasked, e.message, e.maskMessage, e.Args(), e.StackTrace(), e.Data(), e.cause)
+}
@@ -0,0 +1,92 @@
+package errwrap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rapidashorg/errwrap/internal/fmtargs"
+)
+
+// ArgsMismatchError is reported when the number of arguments doesn't match the
+// message format of the error definition
+type ArgsMismatchError struct {
+	Definition *ErrorDefinition
+	Format     string
+	Want       int
+	Got        int
+}
+
+func (e *ArgsMismatchError) Error() string {
+	return fmt.Sprintf("errwrap: %s message format %q wants %d args, got %d", e.Definition.codeString, e.Format, e.Want, e.Got)
+}
+
+// ArgsMismatchHandler is called when the number of arguments passed to
+// ErrorDefinition.NewArgs doesn't match the message format
+type ArgsMismatchHandler func(err error)
+
+// MessageFormat sets the default message format of the error definition, a
+// fmt.Sprintf format used by NewArgs, so call sites only supply arguments and
+// all error wrappers of the definition share the same wording
+func (ed *ErrorDefinition) MessageFormat(format string) *ErrorDefinition {
+	ed.messageFormat = stringPtr(format)
+	return ed
+}
+
+// getMessageFormat returns the message format, it panics with
+// *MissingMessageError if the message format is not set, instead of using the
+// error code string as the format
+func (ed *ErrorDefinition) getMessageFormat(method string) string {
+	if ed.messageFormat == nil {
+		panic(&MissingMessageError{Definition: ed, Method: method, Setter: "MessageFormat"})
+	}
+	return *ed.messageFormat
+}
+
+// CheckArgs checks the number of arguments against the message format, returns
+// *ArgsMismatchError if they don't match, or *MissingMessageError if the
+// message format is not set
+func (ed *ErrorDefinition) CheckArgs(args ...interface{}) error {
+	if ed.messageFormat == nil {
+		return &MissingMessageError{Definition: ed, Method: "CheckArgs", Setter: "MessageFormat"}
+	}
+	format := *ed.messageFormat
+	if want := fmtargs.Count(format); want != len(args) {
+		return &ArgsMismatchError{Definition: ed, Format: format, Want: want, Got: len(args)}
+	}
+	return nil
+}
+
+// NewArgsWithoutContext creates new ErrorWrapper based on error definition
+// using the message format, without passed context. It panics with
+// *MissingMessageError if the message format is not set.
+func (ed *ErrorDefinition) NewArgsWithoutContext(args ...interface{}) ErrorWrapper {
+	format := ed.getMessageFormat("NewArgsWithoutContext")
+	ed.checkArgs(args)
+	erw := newErrorWrapper(context.Background(), ed, format, args...)
+	erw.fillStackTrace(1, ed.stackTraceDepth())
+	return erw
+}
+
+// NewArgs creates new ErrorWrapper based on error definition using the message
+// format. If the number of arguments doesn't match the message format,
+// Config.ArgsMismatchHandler is called. It panics with *MissingMessageError if
+// the message format is not set.
+func (ed *ErrorDefinition) NewArgs(ctx context.Context, args ...interface{}) ErrorWrapper {
+	format := ed.getMessageFormat("NewArgs")
+	ed.checkArgs(args)
+	erw := newErrorWrapper(ctx, ed, format, args...)
+	erw.fillStackTrace(1, ed.stackTraceDepth())
+	return erw
+}
+
+// checkArgs calls the args mismatch handler if the number of arguments doesn't
+// match the message format
+func (ed *ErrorDefinition) checkArgs(args []interface{}) {
+	handler := ed.getFactory().getConfig().ArgsMismatchHandler
+	if handler == nil {
+		return
+	}
+	if err := ed.CheckArgs(args...); err != nil {
+		handler(err)
+	}
+}
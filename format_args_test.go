@@ -0,0 +1,65 @@
+package errwrap
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestErrorDefinition_NewArgs(t *testing.T) {
+	var gotErr error
+	config := DefaultConfig()
+	config.ArgsMismatchHandler = func(err error) {
+		gotErr = err
+	}
+	f := NewFactory(config)
+	ed := f.NewError(100, "ErrUserNotFound", ErrorCategory(1)).MessageFormat("User %d not found in shop %s")
+
+	erw := ed.NewArgs(context.Background(), 1, "foo")
+	if gotErr != nil {
+		t.Errorf("NewArgs() args mismatch error = %v, want nil", gotErr)
+	}
+	if got, want := erw.ActualError(), "User 1 not found in shop foo (100)"; got != want {
+		t.Errorf("NewArgs() ActualError() = %v, want %v", got, want)
+	}
+	if got, want := erw.RawMessage(), "User %d not found in shop %s"; got != want {
+		t.Errorf("NewArgs() RawMessage() = %v, want %v", got, want)
+	}
+
+	ed.NewArgsWithoutContext(1)
+	var mismatch *ArgsMismatchError
+	if !errors.As(gotErr, &mismatch) || mismatch.Want != 2 || mismatch.Got != 1 || mismatch.Definition != ed {
+		t.Fatalf("NewArgsWithoutContext() args mismatch error = %v", gotErr)
+	}
+	if got, want := gotErr.Error(), `errwrap: ErrUserNotFound message format "User %d not found in shop %s" wants 2 args, got 1`; got != want {
+		t.Errorf("ArgsMismatchError.Error() = %v, want %v", got, want)
+	}
+}
+
+func TestErrorDefinition_CheckArgs(t *testing.T) {
+	ed := NewFactory(DefaultConfig()).NewError(100, "ErrTest", ErrorCategory(1))
+
+	var missing *MissingMessageError
+	if err := ed.CheckArgs(); !errors.As(err, &missing) {
+		t.Errorf("CheckArgs() without message format error = %v, want *MissingMessageError", err)
+	}
+	if err := ed.MessageFormat("Invalid %s").CheckArgs("foo", "bar"); err == nil {
+		t.Errorf("CheckArgs() error = nil, want error")
+	}
+}
+
+func TestErrorDefinition_NewArgs_withoutMessageFormat(t *testing.T) {
+	ed := NewFactory(DefaultConfig()).NewError(100, "ErrUserNotFound", ErrorCategory(1))
+
+	defer func() {
+		err, ok := recover().(*MissingMessageError)
+		if !ok || err.Definition != ed {
+			t.Fatalf("NewArgs() without message format panic = %v, want *MissingMessageError", err)
+		}
+		if got, want := err.Error(), "errwrap: NewArgs called on ErrUserNotFound without MessageFormat"; got != want {
+			t.Errorf("MissingMessageError.Error() = %v, want %v", got, want)
+		}
+	}()
+	ed.NewArgs(context.Background(), 1)
+	t.Errorf("NewArgs() without message format doesn't panic")
+}
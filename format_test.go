@@ -0,0 +1,125 @@
+package errwrap
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func Test_errorWrapper_Format(t *testing.T) {
+	type fields struct {
+		code          int
+		codeString    string
+		message       string
+		category      ErrorCategory
+		formatter     MessageFormatter
+		isMasked      bool
+		maskMessage   string
+		maskFormatter MaskFormatter
+		args          []interface{}
+		stackTrace    []string
+		data          ErrorData
+		cause         error
+	}
+	defaultFields := fields{
+		code:          100,
+		codeString:    "ErrTest",
+		message:       "Test error message: %s",
+		category:      ErrorCategory(1),
+		formatter:     DefaultMessageFormatter,
+		isMasked:      true,
+		maskMessage:   "Test masked error message",
+		maskFormatter: DefaultMaskFormatter,
+		args:          []interface{}{"Foo"},
+		stackTrace: []string{
+			"github.com/rapidashorg/errwrap/wrapper.go:1",
+			"github.com/rapidashorg/errwrap/wrapper.go:2",
+		},
+		data: ErrorData{
+			"foo": "bar",
+		},
+		cause: errors.New("an error"),
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		format string
+		want   string
+	}{
+		{
+			name:   "success %s",
+			fields: defaultFields,
+			format: "%s",
+			want:   "Test masked error message (100)",
+		},
+		{
+			name:   "success %v",
+			fields: defaultFields,
+			format: "%v",
+			want:   "Test masked error message (100)",
+		},
+		{
+			name:   "success %q",
+			fields: defaultFields,
+			format: "%q",
+			want:   `"Test masked error message (100)"`,
+		},
+		{
+			name:   "success %+v",
+			fields: defaultFields,
+			format: "%+v",
+			want: "Test error message: Foo (100)\n" +
+				"code string: ErrTest\n" +
+				"category: 1\n" +
+				"data: map[foo:bar]\n" +
+				"stack trace:\n" +
+				"\tgithub.com/rapidashorg/errwrap/wrapper.go:1\n" +
+				"\tgithub.com/rapidashorg/errwrap/wrapper.go:2\n" +
+				"caused by: an error",
+		},
+		{
+			name: "success %+v without data and stack trace",
+			fields: fields{
+				code:       100,
+				codeString: "ErrTest",
+				message:    "Test error message",
+				category:   ErrorCategory(1),
+				formatter:  DefaultMessageFormatter,
+			},
+			format: "%+v",
+			want: "Test error message (100)\n" +
+				"code string: ErrTest\n" +
+				"category: 1",
+		},
+		{
+			name:   "success %#v",
+			fields: defaultFields,
+			format: "%#v",
+			want: `&errwrap.errorWrapper{code:100, codeString:"ErrTest", category:1, isMasked:true, ` +
+				`message:"Test error message: %s", maskMessage:"Test masked error message", args:[]interface {}{"Foo"}, ` +
+				`stackTrace:[]string{"github.com/rapidashorg/errwrap/wrapper.go:1", "github.com/rapidashorg/errwrap/wrapper.go:2"}, ` +
+				`data:errwrap.ErrorData{"foo":"bar"}, cause:&errors.errorString{s:"an error"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &errorWrapper{
+				code:          tt.fields.code,
+				codeString:    tt.fields.codeString,
+				message:       tt.fields.message,
+				category:      tt.fields.category,
+				formatter:     tt.fields.formatter,
+				isMasked:      tt.fields.isMasked,
+				maskMessage:   tt.fields.maskMessage,
+				maskFormatter: tt.fields.maskFormatter,
+				args:          tt.fields.args,
+				stackTrace:    tt.fields.stackTrace,
+				data:          tt.fields.data,
+				cause:         tt.fields.cause,
+			}
+			if got := fmt.Sprintf(tt.format, e); got != tt.want {
+				t.Errorf("errorWrapper.Format() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
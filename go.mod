@@ -1,3 +1,8 @@
 module github.com/rapidashorg/errwrap
 
-go 1.14
+go 1.21
+
+require (
+	github.com/BurntSushi/toml v1.6.0
+	go.yaml.in/yaml/v3 v3.0.5
+)
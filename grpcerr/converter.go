@@ -0,0 +1,246 @@
+// Package grpcerr converts errwrap errors into gRPC statuses and back,
+// choosing the gRPC code from the error category.
+package grpcerr
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/rapidashorg/errwrap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// CategoryUnknown is the category of ErrUnknown. No code is mapped to this
+// category in default, so the unknown errors are returned with codes.Unknown.
+const CategoryUnknown errwrap.ErrorCategory = -1
+
+// MetadataKeyCode is the errdetails.ErrorInfo metadata key containing the
+// error code
+const MetadataKeyCode = "code"
+
+// ErrUnknown is the default fallback error definition, used for errors which
+// don't implement errwrap.ErrorWrapper. The definition is not registered to
+// errwrap.DefaultFactory, so it doesn't collide with application error codes.
+var ErrUnknown = errwrap.NewFactory(errwrap.DefaultConfig()).NewError(0, "ErrUnknown", CategoryUnknown).Masked()
+
+// DefaultConverter is the converter used by package level functions
+var DefaultConverter = NewConverter(ErrUnknown)
+
+// Converter converts errors into gRPC statuses and back. The gRPC code is
+// chosen from the error category, using the codes set by SetCode.
+//
+// The error code, code string and whitelisted error data are carried in
+// errdetails.ErrorInfo status detail: the code string as reason, and the error
+// code and data as metadata. Field violations are carried in
+// errdetails.BadRequest status detail.
+type Converter struct {
+	mu    sync.RWMutex
+	codes map[errwrap.ErrorCategory]codes.Code
+
+	// Fallback is the error definition used to convert errors which don't
+	// implement errwrap.ErrorWrapper, and statuses without errwrap details
+	Fallback *errwrap.ErrorDefinition
+
+	// DefaultCode is the gRPC code used for categories which don't have code
+	// set
+	DefaultCode codes.Code
+
+	// Domain is the errdetails.ErrorInfo domain, e.g. the service name. Only
+	// status details with the same domain are converted back.
+	Domain string
+
+	// DataKeys are the error data keys included as status detail metadata.
+	// The values are formatted using fmt.Sprint, so they are received back as
+	// strings.
+	DataKeys []string
+
+	// Factory resolves error definitions of received statuses from its
+	// registry. errwrap.DefaultFactory is used if this is nil.
+	Factory *errwrap.Factory
+}
+
+// NewConverter creates converter with given fallback error definition
+func NewConverter(fallback *errwrap.ErrorDefinition) *Converter {
+	return &Converter{
+		codes:       make(map[errwrap.ErrorCategory]codes.Code),
+		Fallback:    fallback,
+		DefaultCode: codes.Unknown,
+	}
+}
+
+// SetCode sets the gRPC code used for errors with given category
+func (c *Converter) SetCode(category errwrap.ErrorCategory, code codes.Code) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.codes[category] = code
+}
+
+// Code returns the gRPC code used for errors with given category
+func (c *Converter) Code(category errwrap.ErrorCategory) codes.Code {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if code, ok := c.codes[category]; ok {
+		return code
+	}
+	return c.DefaultCode
+}
+
+// Convert finds errwrap.ErrorWrapper in the error chain, or wraps the error
+// using the fallback error definition if there is none
+func (c *Converter) Convert(ctx context.Context, err error) errwrap.ErrorWrapper {
+	if erw, ok := errwrap.As(err); ok {
+		return erw
+	}
+	return c.Fallback.Wrap(ctx, err, "%v", err)
+}
+
+// Status converts the error into gRPC status. The message is masked if the
+// error is masked, so the status is safe to be sent to the client, and is
+// localized to the locale injected into context.
+func (c *Converter) Status(ctx context.Context, err error) *status.Status {
+	erw := c.Convert(ctx, err)
+
+	info := &errdetails.ErrorInfo{
+		Reason: erw.CodeString(),
+		Domain: c.Domain,
+		Metadata: map[string]string{
+			MetadataKeyCode: strconv.Itoa(erw.Code()),
+		},
+	}
+
+	data := erw.Data()
+	for _, k := range c.DataKeys {
+		if v, ok := data[k]; ok && k != MetadataKeyCode {
+			info.Metadata[k] = fmt.Sprint(v)
+		}
+	}
+
+	details := []protoadapt.MessageV1{info}
+	if fields := erw.Fields(); len(fields) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, field := range fields {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field.Field,
+				Reason:      field.Code,
+				Description: field.Message,
+			})
+		}
+		details = append(details, badRequest)
+	}
+
+	st := status.New(c.Code(erw.Category()), erw.LocalizedError(ctx))
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st
+}
+
+// Error converts the error into gRPC status error, nil error is returned as is
+func (c *Converter) Error(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := errwrap.As(err); !ok {
+		// keep errors which are already gRPC statuses, e.g. context errors
+		// converted by grpc
+		if st, ok := status.FromError(err); ok {
+			return st.Err()
+		}
+	}
+	return c.Status(ctx, err).Err()
+}
+
+// FromStatus rebuilds ErrorWrapper from the status, resolving the error
+// definition from the factory registry. Statuses without errwrap details are
+// wrapped using the fallback error definition. The status is kept as the cause,
+// so status.Code and status.FromError still work on the returned error.
+func (c *Converter) FromStatus(st *status.Status) errwrap.ErrorWrapper {
+	if st.Code() == codes.OK {
+		return nil
+	}
+
+	var fields []errwrap.FieldViolation
+	var info *errdetails.ErrorInfo
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if info == nil && detail.GetDomain() == c.Domain {
+				info = detail
+			}
+
+		case *errdetails.BadRequest:
+			for _, violation := range detail.GetFieldViolations() {
+				fields = append(fields, errwrap.FieldViolation{
+					Field:   violation.GetField(),
+					Code:    violation.GetReason(),
+					Message: violation.GetDescription(),
+				})
+			}
+		}
+	}
+
+	if info != nil {
+		code, _ := strconv.Atoi(info.GetMetadata()[MetadataKeyCode])
+
+		var data errwrap.ErrorData
+		for k, v := range info.GetMetadata() {
+			if k == MetadataKeyCode {
+				continue
+			}
+			if data == nil {
+				data = make(errwrap.ErrorData)
+			}
+			data[k] = v
+		}
+
+		erw := c.getFactory().RestoreError(code, info.GetReason(), st.Message(), data)
+		return errwrap.WithFields(errwrap.WithCause(erw, st.Err()), fields...)
+	}
+
+	erw := c.Fallback.WrapWithoutContext(st.Err(), "%s", st.Message())
+	return errwrap.WithFields(erw, fields...)
+}
+
+// FromError converts gRPC status error into ErrorWrapper using FromStatus.
+// Errors which are not gRPC statuses are returned as is.
+func (c *Converter) FromError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	return c.FromStatus(st)
+}
+
+func (c *Converter) getFactory() *errwrap.Factory {
+	if c.Factory == nil {
+		return errwrap.DefaultFactory
+	}
+	return c.Factory
+}
+
+// SetCode sets the gRPC code used by DefaultConverter for errors with given
+// category
+func SetCode(category errwrap.ErrorCategory, code codes.Code) {
+	DefaultConverter.SetCode(category, code)
+}
+
+// Status converts the error into gRPC status using DefaultConverter
+func Status(ctx context.Context, err error) *status.Status {
+	return DefaultConverter.Status(ctx, err)
+}
+
+// FromStatus rebuilds ErrorWrapper from the status using DefaultConverter
+func FromStatus(st *status.Status) errwrap.ErrorWrapper {
+	return DefaultConverter.FromStatus(st)
+}
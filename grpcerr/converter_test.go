@@ -0,0 +1,236 @@
+package grpcerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/rapidashorg/errwrap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	categoryBadRequest errwrap.ErrorCategory = iota
+	categoryNotFound
+)
+
+var (
+	testFactory = errwrap.NewFactory(errwrap.DefaultConfig())
+
+	errBadRequest = testFactory.NewError(100, "ErrBadRequest", categoryBadRequest)
+	errNotFound   = testFactory.NewError(101, "ErrNotFound", categoryNotFound).MaskedMessage("Not found")
+)
+
+func newTestConverter() *Converter {
+	c := NewConverter(ErrUnknown)
+	c.SetCode(categoryBadRequest, codes.InvalidArgument)
+	c.SetCode(categoryNotFound, codes.NotFound)
+	c.Domain = "test.example.com"
+	c.DataKeys = []string{"user_id"}
+	c.Factory = testFactory
+	return c
+}
+
+func TestConverter_Code(t *testing.T) {
+	tests := []struct {
+		name     string
+		category errwrap.ErrorCategory
+		want     codes.Code
+	}{
+		{
+			name:     "success",
+			category: categoryNotFound,
+			want:     codes.NotFound,
+		},
+		{
+			name:     "success code not set",
+			category: errwrap.ErrorCategory(10),
+			want:     codes.Unknown,
+		},
+		{
+			name:     "success unknown category",
+			category: CategoryUnknown,
+			want:     codes.Unknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newTestConverter().Code(tt.category); got != tt.want {
+				t.Errorf("Converter.Code() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverter_Status(t *testing.T) {
+	ctx := errwrap.InjectErrorData(context.Background(), errwrap.ErrorData{
+		"user_id": 1,
+		"token":   "secret",
+	})
+
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    codes.Code
+		wantMessage string
+		wantInfo    *errdetails.ErrorInfo
+	}{
+		{
+			name:        "success",
+			err:         errBadRequest.New(ctx, "Invalid name: %s", "foo"),
+			wantCode:    codes.InvalidArgument,
+			wantMessage: "Invalid name: foo (100)",
+			wantInfo: &errdetails.ErrorInfo{
+				Reason:   "ErrBadRequest",
+				Domain:   "test.example.com",
+				Metadata: map[string]string{"code": "100", "user_id": "1"},
+			},
+		},
+		{
+			name:        "success masked and wrapped",
+			err:         fmt.Errorf("repository: %w", errNotFound.NewWithoutContext("User %d not found", 1)),
+			wantCode:    codes.NotFound,
+			wantMessage: "Not found (101)",
+			wantInfo: &errdetails.ErrorInfo{
+				Reason:   "ErrNotFound",
+				Domain:   "test.example.com",
+				Metadata: map[string]string{"code": "101"},
+			},
+		},
+		{
+			name:        "success not error wrapper",
+			err:         errors.New("connection refused"),
+			wantCode:    codes.Unknown,
+			wantMessage: errwrap.DefaultMaskMessage + " (0)",
+			wantInfo: &errdetails.ErrorInfo{
+				Reason:   "ErrUnknown",
+				Domain:   "test.example.com",
+				Metadata: map[string]string{"code": "0", "user_id": "1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newTestConverter().Status(ctx, tt.err)
+
+			if st.Code() != tt.wantCode {
+				t.Errorf("Converter.Status() code = %v, want %v", st.Code(), tt.wantCode)
+			}
+			if st.Message() != tt.wantMessage {
+				t.Errorf("Converter.Status() message = %v, want %v", st.Message(), tt.wantMessage)
+			}
+
+			details := st.Details()
+			if len(details) != 1 {
+				t.Fatalf("Converter.Status() details = %v, want 1 detail", details)
+			}
+			info := details[0].(*errdetails.ErrorInfo)
+			if info.GetReason() != tt.wantInfo.GetReason() || info.GetDomain() != tt.wantInfo.GetDomain() ||
+				!reflect.DeepEqual(info.GetMetadata(), tt.wantInfo.GetMetadata()) {
+				t.Errorf("Converter.Status() detail = %v, want %v", info, tt.wantInfo)
+			}
+		})
+	}
+}
+
+func TestConverter_Error(t *testing.T) {
+	c := newTestConverter()
+
+	if got := c.Error(context.Background(), nil); got != nil {
+		t.Errorf("Converter.Error() = %v, want nil", got)
+	}
+
+	canceled := status.Error(codes.Canceled, "context canceled")
+	if got := status.Code(c.Error(context.Background(), canceled)); got != codes.Canceled {
+		t.Errorf("Converter.Error() code = %v, want %v", got, codes.Canceled)
+	}
+}
+
+func TestConverter_FromStatus(t *testing.T) {
+	c := newTestConverter()
+
+	tests := []struct {
+		name           string
+		st             *status.Status
+		wantDefinition *errwrap.ErrorDefinition
+		wantCode       int
+		wantMessage    string
+		wantData       errwrap.ErrorData
+		wantFields     []errwrap.FieldViolation
+	}{
+		{
+			name:           "success",
+			st:             c.Status(context.Background(), errNotFound.New(errwrap.InjectErrorData(context.Background(), errwrap.ErrorData{"user_id": "u-1"}), "User not found")),
+			wantDefinition: errNotFound,
+			wantCode:       101,
+			wantMessage:    "Not found (101)",
+			wantData:       errwrap.ErrorData{"user_id": "u-1"},
+		},
+		{
+			name: "success with fields",
+			st: c.Status(context.Background(), errwrap.WithFields(errBadRequest.NewWithoutContext("Invalid body"),
+				errwrap.FieldViolation{Field: "name", Code: "required", Message: "Name is required"})),
+			wantDefinition: errBadRequest,
+			wantCode:       100,
+			wantMessage:    "Invalid body (100)",
+			wantFields:     []errwrap.FieldViolation{{Field: "name", Code: "required", Message: "Name is required"}},
+		},
+		{
+			name: "success multi error with fields",
+			st: c.Status(context.Background(), errwrap.NewMultiError(
+				errwrap.WithFields(errBadRequest.NewWithoutContext("Invalid name"), errwrap.FieldViolation{Field: "name", Code: "required", Message: "Name is required"}),
+				errwrap.WithFields(errBadRequest.NewWithoutContext("Invalid email"), errwrap.FieldViolation{Field: "email", Code: "invalid", Message: "Email is invalid"}),
+			)),
+			wantDefinition: errBadRequest,
+			wantCode:       100,
+			wantMessage:    "Invalid name (100); Invalid email (100)",
+			wantFields: []errwrap.FieldViolation{
+				{Field: "name", Code: "required", Message: "Name is required"},
+				{Field: "email", Code: "invalid", Message: "Email is invalid"},
+			},
+		},
+		{
+			name:           "success without details",
+			st:             status.New(codes.Unavailable, "connection refused"),
+			wantDefinition: ErrUnknown,
+			wantCode:       0,
+			wantMessage:    "connection refused (0)",
+		},
+		{
+			name:     "success ok",
+			st:       status.New(codes.OK, ""),
+			wantCode: -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.FromStatus(tt.st)
+			if tt.wantCode == -1 {
+				if got != nil {
+					t.Errorf("Converter.FromStatus() = %v, want nil", got)
+				}
+				return
+			}
+
+			if !got.Is(tt.wantDefinition) {
+				t.Errorf("Converter.FromStatus() = %v, want %v", got.CodeString(), tt.wantDefinition.CodeString())
+			}
+			if got.Code() != tt.wantCode {
+				t.Errorf("Converter.FromStatus() Code() = %v, want %v", got.Code(), tt.wantCode)
+			}
+			if got.ActualError() != tt.wantMessage {
+				t.Errorf("Converter.FromStatus() ActualError() = %v, want %v", got.ActualError(), tt.wantMessage)
+			}
+			if !reflect.DeepEqual(got.Data(), tt.wantData) {
+				t.Errorf("Converter.FromStatus() Data() = %v, want %v", got.Data(), tt.wantData)
+			}
+			if !reflect.DeepEqual(got.Fields(), tt.wantFields) {
+				t.Errorf("Converter.FromStatus() Fields() = %v, want %v", got.Fields(), tt.wantFields)
+			}
+		})
+	}
+}
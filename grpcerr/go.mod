@@ -0,0 +1,17 @@
+module github.com/rapidashorg/errwrap/grpcerr
+
+go 1.25.0
+
+require (
+	github.com/rapidashorg/errwrap v0.0.5-0.20261017060712-c825836de0bb
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260706201446-f0a921348800
+	google.golang.org/grpc v1.84.0
+	google.golang.org/protobuf v1.36.11
+)
+
+require (
+	github.com/BurntSushi/toml v1.6.0 // indirect
+	golang.org/x/net v0.57.0 // indirect
+	golang.org/x/sys v0.47.0 // indirect
+	golang.org/x/text v0.40.0 // indirect
+)
@@ -0,0 +1,91 @@
+package grpcerr
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/grpc"
+)
+
+// UnaryServerInterceptor converts errors returned by unary handlers into gRPC
+// statuses
+func (c *Converter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		return resp, c.Error(ctx, err)
+	}
+}
+
+// StreamServerInterceptor converts errors returned by stream handlers into
+// gRPC statuses
+func (c *Converter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return c.Error(ss.Context(), handler(srv, ss))
+	}
+}
+
+// UnaryClientInterceptor converts gRPC statuses received by unary calls back
+// into errwrap errors
+func (c *Converter) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return c.FromError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientInterceptor converts gRPC statuses received by stream calls
+// back into errwrap errors
+func (c *Converter) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, c.FromError(err)
+		}
+		return &clientStream{ClientStream: cs, converter: c}, nil
+	}
+}
+
+// clientStream converts errors returned by the client stream, except io.EOF
+// which marks the end of the stream
+type clientStream struct {
+	grpc.ClientStream
+	converter *Converter
+}
+
+func (cs *clientStream) SendMsg(m interface{}) error {
+	return cs.convert(cs.ClientStream.SendMsg(m))
+}
+
+func (cs *clientStream) RecvMsg(m interface{}) error {
+	return cs.convert(cs.ClientStream.RecvMsg(m))
+}
+
+func (cs *clientStream) convert(err error) error {
+	if err == io.EOF {
+		return err
+	}
+	return cs.converter.FromError(err)
+}
+
+// UnaryServerInterceptor converts errors returned by unary handlers using
+// DefaultConverter
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return DefaultConverter.UnaryServerInterceptor()
+}
+
+// StreamServerInterceptor converts errors returned by stream handlers using
+// DefaultConverter
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return DefaultConverter.StreamServerInterceptor()
+}
+
+// UnaryClientInterceptor converts statuses received by unary calls using
+// DefaultConverter
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return DefaultConverter.UnaryClientInterceptor()
+}
+
+// StreamClientInterceptor converts statuses received by stream calls using
+// DefaultConverter
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return DefaultConverter.StreamClientInterceptor()
+}
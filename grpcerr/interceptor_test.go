@@ -0,0 +1,122 @@
+package grpcerr
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/rapidashorg/errwrap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// healthServer returns the error for every call
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	err error
+}
+
+func (s *healthServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	return nil, s.err
+}
+
+func (s *healthServer) Watch(req *grpc_health_v1.HealthCheckRequest, ss grpc_health_v1.Health_WatchServer) error {
+	return s.err
+}
+
+// newTestClient starts in-process server using the converter interceptors,
+// and returns client connected to it
+func newTestClient(t *testing.T, c *Converter, err error) grpc_health_v1.HealthClient {
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(c.UnaryServerInterceptor()),
+		grpc.StreamInterceptor(c.StreamServerInterceptor()),
+	)
+	grpc_health_v1.RegisterHealthServer(srv, &healthServer{err: err})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(c.UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(c.StreamClientInterceptor()),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+func TestInterceptors(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		wantDefinition *errwrap.ErrorDefinition
+		wantCode       codes.Code
+		wantMessage    string
+	}{
+		{
+			name:           "success",
+			err:            errBadRequest.NewWithoutContext("Invalid service name"),
+			wantDefinition: errBadRequest,
+			wantCode:       codes.InvalidArgument,
+			wantMessage:    "Invalid service name (100)",
+		},
+		{
+			name:           "success masked",
+			err:            errNotFound.NewWithoutContext("Service %s not found", "foo"),
+			wantDefinition: errNotFound,
+			wantCode:       codes.NotFound,
+			wantMessage:    "Not found (101)",
+		},
+		{
+			name:           "success not error wrapper",
+			err:            errors.New("connection refused"),
+			wantDefinition: ErrUnknown,
+			wantCode:       codes.Unknown,
+			wantMessage:    errwrap.DefaultMaskMessage + " (0)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, newTestConverter(), tt.err)
+			req := &grpc_health_v1.HealthCheckRequest{Service: "foo"}
+
+			_, err := client.Check(context.Background(), req)
+			assertError(t, "unary", err, tt.wantDefinition, tt.wantCode, tt.wantMessage)
+
+			stream, err := client.Watch(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Watch() error = %v", err)
+			}
+			_, err = stream.Recv()
+			assertError(t, "stream", err, tt.wantDefinition, tt.wantCode, tt.wantMessage)
+		})
+	}
+}
+
+func assertError(t *testing.T, call string, err error, wantDefinition *errwrap.ErrorDefinition, wantCode codes.Code, wantMessage string) {
+	t.Helper()
+
+	if got := status.Code(err); got != wantCode {
+		t.Errorf("%s call status.Code() = %v, want %v", call, got, wantCode)
+	}
+	if !errors.Is(err, wantDefinition) {
+		t.Errorf("%s call error = %v, want %v", call, err, wantDefinition)
+		return
+	}
+	erw, _ := errwrap.As(err)
+	if erw.ActualError() != wantMessage {
+		t.Errorf("%s call ActualError() = %v, want %v", call, erw.ActualError(), wantMessage)
+	}
+}
@@ -0,0 +1,59 @@
+package httperr
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// HandlerFunc is an HTTP handler which returns error
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// Handle converts the handler into http.Handler. Returned errors and recovered
+// panics are written as responses using the writer.
+func (wr *Writer) Handle(fn HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer wr.recover(w, r)
+
+		if err := fn(w, r); err != nil {
+			wr.WriteError(w, r, err)
+		}
+	})
+}
+
+// Middleware recovers panics from the next handler, and writes them as
+// responses using the writer
+func (wr *Writer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer wr.recover(w, r)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// recover recovers panic and writes it as response. http.ErrAbortHandler is
+// panicked again, as it is used to abort the response.
+func (wr *Writer) recover(w http.ResponseWriter, r *http.Request) {
+	rec := recover()
+	if rec == nil {
+		return
+	}
+	if rec == http.ErrAbortHandler {
+		panic(rec)
+	}
+
+	err, ok := rec.(error)
+	if !ok {
+		err = fmt.Errorf("%v", rec)
+	}
+	wr.WriteError(w, r, wr.Fallback.Wrap(r.Context(), err, "panic: %v", rec))
+}
+
+// Handle converts the handler into http.Handler using DefaultWriter
+func Handle(fn HandlerFunc) http.Handler {
+	return DefaultWriter.Handle(fn)
+}
+
+// Middleware recovers panics from the next handler using DefaultWriter
+func Middleware(next http.Handler) http.Handler {
+	return DefaultWriter.Middleware(next)
+}
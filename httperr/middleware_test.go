@@ -0,0 +1,108 @@
+package httperr
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWriter_Handle(t *testing.T) {
+	tests := []struct {
+		name       string
+		fn         HandlerFunc
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "success",
+			fn: func(w http.ResponseWriter, r *http.Request) error {
+				w.Write([]byte("ok"))
+				return nil
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "ok",
+		},
+		{
+			name: "success returned error",
+			fn: func(w http.ResponseWriter, r *http.Request) error {
+				return errBadRequest.New(r.Context(), "Invalid body")
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"message":"Invalid body (100)","code":100}` + "\n",
+		},
+		{
+			name: "success recovered panic",
+			fn: func(w http.ResponseWriter, r *http.Request) error {
+				panic("nil map")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"message":"Sorry, there are internal server error occured, please try again later. (0)","code":0}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wr := newTestWriter()
+			wr.RequestID = nil
+
+			w := httptest.NewRecorder()
+			wr.Handle(tt.fn).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Writer.Handle() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("Writer.Handle() body = %v, want %v", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestWriter_Middleware(t *testing.T) {
+	errPanic := errors.New("an error")
+
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantStatus int
+		wantPanic  interface{}
+	}{
+		{
+			name: "success",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "success recovered panic",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				panic(errPanic)
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "success abort handler panicked again",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				panic(http.ErrAbortHandler)
+			},
+			wantPanic: http.ErrAbortHandler,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if got := recover(); got != tt.wantPanic {
+					t.Errorf("Writer.Middleware() panic = %v, want %v", got, tt.wantPanic)
+				}
+			}()
+
+			w := httptest.NewRecorder()
+			newTestWriter().Middleware(tt.handler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Writer.Middleware() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
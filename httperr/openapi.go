@@ -0,0 +1,152 @@
+package httperr
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// OpenAPIErrorSchema is the name of the OpenAPI schema of Response, which the
+// schema of each error definition extends
+const OpenAPIErrorSchema = "Error"
+
+// OpenAPIComponents is the subset of OpenAPI components object documenting the
+// error definitions, to be merged into the OpenAPI document
+type OpenAPIComponents struct {
+	Schemas   map[string]*OpenAPISchema   `json:"schemas"`
+	Responses map[string]*OpenAPIResponse `json:"responses"`
+}
+
+// OpenAPISchema is the subset of OpenAPI schema object
+type OpenAPISchema struct {
+	Ref         string                    `json:"$ref,omitempty"`
+	Type        string                    `json:"type,omitempty"`
+	Description string                    `json:"description,omitempty"`
+	Properties  map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Required    []string                  `json:"required,omitempty"`
+	Items       *OpenAPISchema            `json:"items,omitempty"`
+	AllOf       []*OpenAPISchema          `json:"allOf,omitempty"`
+	Enum        []interface{}             `json:"enum,omitempty"`
+	Example     interface{}               `json:"example,omitempty"`
+
+	*OpenAPIErrorInfo
+}
+
+// OpenAPIErrorInfo documents the error definition in its schema using
+// specification extensions
+type OpenAPIErrorInfo struct {
+	CodeString string `json:"x-code-string"`
+	Category   int    `json:"x-category"`
+	Status     int    `json:"x-status"`
+	Masked     bool   `json:"x-masked"`
+}
+
+// OpenAPIResponse is the subset of OpenAPI response object
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIMediaType is the subset of OpenAPI media type object
+type OpenAPIMediaType struct {
+	Schema  *OpenAPISchema `json:"schema"`
+	Example interface{}    `json:"example,omitempty"`
+}
+
+// OpenAPI documents the references as OpenAPI components. Each error
+// definition has a schema extending OpenAPIErrorSchema, and a response
+// referencing the schema, both named by the error code string, e.g.
+// "#/components/responses/ErrNotFound". Returns error if the names of two
+// code strings are the same after replacing the characters not allowed in
+// component names, or if the name is OpenAPIErrorSchema.
+func OpenAPI(refs []Reference) (*OpenAPIComponents, error) {
+	c := &OpenAPIComponents{
+		Schemas: map[string]*OpenAPISchema{
+			OpenAPIErrorSchema: responseSchema(),
+		},
+		Responses: make(map[string]*OpenAPIResponse, len(refs)),
+	}
+
+	codeStrings := make(map[string]string, len(refs))
+	for _, ref := range refs {
+		name := openAPIName(ref.CodeString)
+		if name == OpenAPIErrorSchema {
+			return nil, fmt.Errorf("httperr: OpenAPI component name of code string %q conflicts with %s schema", ref.CodeString, OpenAPIErrorSchema)
+		}
+		if codeString, ok := codeStrings[name]; ok {
+			return nil, fmt.Errorf("httperr: OpenAPI component name %q is used by code strings %q and %q", name, codeString, ref.CodeString)
+		}
+		codeStrings[name] = ref.CodeString
+
+		c.Schemas[name] = &OpenAPISchema{
+			Description: ref.Message,
+			AllOf: []*OpenAPISchema{
+				{Ref: "#/components/schemas/" + OpenAPIErrorSchema},
+				{
+					Type: "object",
+					Properties: map[string]*OpenAPISchema{
+						"message": {Type: "string", Example: ref.Message},
+						"code":    {Type: "integer", Enum: []interface{}{ref.Code}},
+					},
+				},
+			},
+			OpenAPIErrorInfo: &OpenAPIErrorInfo{
+				CodeString: ref.CodeString,
+				Category:   int(ref.Category),
+				Status:     ref.Status,
+				Masked:     ref.Masked,
+			},
+		}
+
+		description := fmt.Sprintf("%d %s: %s", ref.Status, http.StatusText(ref.Status), ref.CodeString)
+		if ref.Message != "" {
+			description += ", " + ref.Message
+		}
+		c.Responses[name] = &OpenAPIResponse{
+			Description: description,
+			Content: map[string]*OpenAPIMediaType{
+				"application/json": {
+					Schema:  &OpenAPISchema{Ref: "#/components/schemas/" + name},
+					Example: Response{Message: ref.Message, Code: ref.Code},
+				},
+			},
+		}
+	}
+	return c, nil
+}
+
+// responseSchema is the schema of Response
+func responseSchema() *OpenAPISchema {
+	return &OpenAPISchema{
+		Type: "object",
+		Properties: map[string]*OpenAPISchema{
+			"message": {Type: "string"},
+			"code":    {Type: "integer"},
+			"fields": {
+				Type: "array",
+				Items: &OpenAPISchema{
+					Type: "object",
+					Properties: map[string]*OpenAPISchema{
+						"field":   {Type: "string"},
+						"code":    {Type: "string"},
+						"message": {Type: "string"},
+					},
+					Required: []string{"field", "code", "message"},
+				},
+			},
+			"request_id": {Type: "string"},
+		},
+		Required: []string{"message", "code"},
+	}
+}
+
+// openAPIName replaces characters not allowed in OpenAPI component names
+func openAPIName(codeString string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, codeString)
+}
@@ -0,0 +1,99 @@
+package httperr
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/rapidashorg/errwrap"
+)
+
+func TestOpenAPI(t *testing.T) {
+	refs := newTestWriter().References([]*errwrap.ErrorDefinition{errNotFound})
+	refs = append(refs, Reference{Code: 900, CodeString: "errors/invalid name", Status: 400})
+
+	components, err := OpenAPI(refs)
+	if err != nil {
+		t.Fatalf("OpenAPI() error = %v", err)
+	}
+	data, err := json.Marshal(components)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var got map[string]map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	wantSchema := map[string]interface{}{
+		"description": "Not found",
+		"allOf": []interface{}{
+			map[string]interface{}{"$ref": "#/components/schemas/Error"},
+			map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"message": map[string]interface{}{"type": "string", "example": "Not found"},
+					"code":    map[string]interface{}{"type": "integer", "enum": []interface{}{101.0}},
+				},
+			},
+		},
+		"x-code-string": "ErrNotFound",
+		"x-category":    1.0,
+		"x-status":      404.0,
+		"x-masked":      true,
+	}
+	if schema := got["schemas"]["ErrNotFound"]; !reflect.DeepEqual(schema, wantSchema) {
+		t.Errorf("OpenAPI() schema = %v, want %v", schema, wantSchema)
+	}
+
+	wantResponse := map[string]interface{}{
+		"description": "404 Not Found: ErrNotFound, Not found",
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema":  map[string]interface{}{"$ref": "#/components/schemas/ErrNotFound"},
+				"example": map[string]interface{}{"message": "Not found", "code": 101.0},
+			},
+		},
+	}
+	if response := got["responses"]["ErrNotFound"]; !reflect.DeepEqual(response, wantResponse) {
+		t.Errorf("OpenAPI() response = %v, want %v", response, wantResponse)
+	}
+
+	if _, ok := got["schemas"][OpenAPIErrorSchema]; !ok {
+		t.Errorf("OpenAPI() schemas doesn't contain %s schema", OpenAPIErrorSchema)
+	}
+	if _, ok := got["responses"]["errors_invalid_name"]; !ok {
+		t.Errorf("OpenAPI() responses = %v, want errors_invalid_name response", got["responses"])
+	}
+}
+
+func TestOpenAPI_conflict(t *testing.T) {
+	tests := []struct {
+		name    string
+		refs    []Reference
+		wantErr string
+	}{
+		{
+			name:    "fail same name",
+			refs:    []Reference{{Code: 1, CodeString: "errors/invalid"}, {Code: 2, CodeString: "errors.invalid"}, {Code: 3, CodeString: "errors invalid"}},
+			wantErr: `httperr: OpenAPI component name "errors_invalid" is used by code strings "errors/invalid" and "errors invalid"`,
+		},
+		{
+			name:    "fail error schema name",
+			refs:    []Reference{{Code: 1, CodeString: OpenAPIErrorSchema}},
+			wantErr: `httperr: OpenAPI component name of code string "Error" conflicts with Error schema`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := OpenAPI(tt.refs)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("OpenAPI() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != nil {
+				t.Errorf("OpenAPI() = %v, want nil", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package httperr
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/rapidashorg/errwrap"
+)
+
+// ProblemContentType is the media type of problem details document
+const ProblemContentType = "application/problem+json"
+
+// problemMembers are the members defined by RFC 7807, which can't be used as
+// extension members
+var problemMembers = map[string]bool{
+	"type":     true,
+	"title":    true,
+	"status":   true,
+	"detail":   true,
+	"instance": true,
+}
+
+// Problem is problem details document defined by RFC 7807
+type Problem struct {
+	Type     string
+	Title    string
+	Status   int
+	Detail   string
+	Instance string
+
+	// Extensions contains extension members, e.g. error code and selected
+	// error data
+	Extensions map[string]interface{}
+}
+
+// MarshalJSON marshals the problem with extension members flattened into the
+// document
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	doc := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		if !problemMembers[k] {
+			doc[k] = v
+		}
+	}
+
+	doc["type"] = p.Type
+	doc["title"] = p.Title
+	if p.Status != 0 {
+		doc["status"] = p.Status
+	}
+	if p.Detail != "" {
+		doc["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		doc["instance"] = p.Instance
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON unmarshals the problem, members not defined by RFC 7807 are
+// stored as extension members
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var doc struct {
+		Type     string `json:"type"`
+		Title    string `json:"title"`
+		Status   int    `json:"status"`
+		Detail   string `json:"detail"`
+		Instance string `json:"instance"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	var extensions map[string]interface{}
+	if err := json.Unmarshal(data, &extensions); err != nil {
+		return err
+	}
+	for k := range problemMembers {
+		delete(extensions, k)
+	}
+
+	*p = Problem{
+		Type:       doc.Type,
+		Title:      doc.Title,
+		Status:     doc.Status,
+		Detail:     doc.Detail,
+		Instance:   doc.Instance,
+		Extensions: extensions,
+	}
+	return nil
+}
+
+// Restore rebuilds ErrorWrapper from the problem, resolving the error
+// definition from the factory registry. The error code string is the problem
+// type without typePrefix, the error code is taken from "code" extension
+// member, and the field violations from "fields" extension member. Other
+// extension members are restored as error data.
+func (p *Problem) Restore(f *errwrap.Factory, typePrefix string) errwrap.ErrorWrapper {
+	codeString := ""
+	if strings.HasPrefix(p.Type, typePrefix) && p.Type != "about:blank" {
+		codeString = strings.TrimPrefix(p.Type, typePrefix)
+	}
+
+	code := 0
+	var fields []errwrap.FieldViolation
+	var data errwrap.ErrorData
+	for k, v := range p.Extensions {
+		switch k {
+		case "code":
+			if c, ok := v.(float64); ok {
+				code = int(c)
+			}
+			continue
+
+		case "fields":
+			fields = decodeFields(v)
+			continue
+		}
+
+		if data == nil {
+			data = make(errwrap.ErrorData)
+		}
+		data[k] = v
+	}
+
+	message := p.Detail
+	if message == "" {
+		message = p.Title
+	}
+
+	return errwrap.WithFields(f.RestoreError(code, codeString, message, data), fields...)
+}
+
+// decodeFields decodes field violations from the unmarshalled extension
+// member, invalid field violations are ignored
+func decodeFields(v interface{}) []errwrap.FieldViolation {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	var fields []errwrap.FieldViolation
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil
+	}
+	return fields
+}
+
+// Problem converts the error into problem details document. The title is the
+// localized message of the error, masked if the error is masked. The detail
+// is the actual error message, only filled if the request is trusted.
+func (wr *Writer) Problem(r *http.Request, err error) *Problem {
+	erw := wr.Convert(r, err)
+
+	p := &Problem{
+		Type:     wr.ProblemTypePrefix + erw.CodeString(),
+		Title:    erw.LocalizedError(r.Context()),
+		Status:   wr.Status(erw.Category()),
+		Instance: r.URL.RequestURI(),
+		Extensions: map[string]interface{}{
+			"code": erw.Code(),
+		},
+	}
+	if fields := erw.Fields(); len(fields) > 0 {
+		p.Extensions["fields"] = fields
+	}
+	if wr.Trusted != nil && wr.Trusted(r) {
+		p.Detail = erw.ActualError()
+	}
+	if wr.RequestID != nil {
+		if requestID := wr.RequestID(r); requestID != "" {
+			p.Extensions["request_id"] = requestID
+		}
+	}
+
+	data := erw.Data()
+	for _, k := range wr.ProblemDataKeys {
+		if v, ok := data[k]; ok {
+			p.Extensions[k] = v
+		}
+	}
+	return p
+}
+
+// WriteProblem writes the error as problem details document response
+func (wr *Writer) WriteProblem(w http.ResponseWriter, r *http.Request, err error) {
+	p := wr.Problem(r, err)
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// WriteProblem writes the error as problem details document response using
+// DefaultWriter
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) {
+	DefaultWriter.WriteProblem(w, r, err)
+}
+
+// DecodeProblem decodes problem details document into ErrorWrapper, resolving
+// the error definition from errwrap.DefaultFactory registry
+func DecodeProblem(data []byte, typePrefix string) (errwrap.ErrorWrapper, error) {
+	var p Problem
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	return p.Restore(errwrap.DefaultFactory, typePrefix), nil
+}
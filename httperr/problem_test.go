@@ -0,0 +1,191 @@
+package httperr
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/rapidashorg/errwrap"
+)
+
+func TestWriter_WriteProblem(t *testing.T) {
+	ctx := errwrap.InjectErrorData(context.Background(), errwrap.ErrorData{
+		"user_id": "u-1",
+		"token":   "secret",
+	})
+
+	tests := []struct {
+		name       string
+		err        error
+		trusted    bool
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "success",
+			err:        errNotFound.New(ctx, "User %s not found", "u-1"),
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"code":101,"instance":"/users/u-1","request_id":"req-1","status":404,"title":"Not found (101)","type":"https://example.com/errors/ErrNotFound","user_id":"u-1"}` + "\n",
+		},
+		{
+			name:       "success with fields",
+			err:        errwrap.WithFields(errBadRequest.New(ctx, "Invalid body"), errwrap.FieldViolation{Field: "name", Code: "required", Message: "Name is required"}),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"code":100,"fields":[{"field":"name","code":"required","message":"Name is required"}],"instance":"/users/u-1","request_id":"req-1","status":400,"title":"Invalid body (100)","type":"https://example.com/errors/ErrBadRequest","user_id":"u-1"}` + "\n",
+		},
+		{
+			name: "success multi error with fields",
+			err: errwrap.NewMultiError(
+				errwrap.WithFields(errBadRequest.New(ctx, "Invalid name"), errwrap.FieldViolation{Field: "name", Code: "required", Message: "Name is required"}),
+				errwrap.WithFields(errBadRequest.New(ctx, "Invalid email"), errwrap.FieldViolation{Field: "email", Code: "invalid", Message: "Email is invalid"}),
+			),
+			wantStatus: http.StatusBadRequest,
+			wantBody: `{"code":100,"fields":[{"field":"name","code":"required","message":"Name is required"},` +
+				`{"field":"email","code":"invalid","message":"Email is invalid"}],"instance":"/users/u-1","request_id":"req-1",` +
+				`"status":400,"title":"Invalid name (100); Invalid email (100)","type":"https://example.com/errors/ErrBadRequest","user_id":"u-1"}` + "\n",
+		},
+		{
+			name:       "success trusted",
+			err:        errNotFound.New(ctx, "User %s not found", "u-1"),
+			trusted:    true,
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"code":101,"detail":"User u-1 not found (101)","instance":"/users/u-1","request_id":"req-1","status":404,"title":"Not found (101)","type":"https://example.com/errors/ErrNotFound","user_id":"u-1"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/users/u-1", nil)
+			r.Header.Set("X-Request-Id", "req-1")
+			w := httptest.NewRecorder()
+
+			wr := newTestWriter()
+			wr.ProblemTypePrefix = "https://example.com/errors/"
+			wr.ProblemDataKeys = []string{"user_id", "missing"}
+			wr.Trusted = func(r *http.Request) bool { return tt.trusted }
+			wr.WriteProblem(w, r, tt.err)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Writer.WriteProblem() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("Writer.WriteProblem() body = %v, want %v", got, tt.wantBody)
+			}
+			if got := w.Header().Get("Content-Type"); got != ProblemContentType {
+				t.Errorf("Writer.WriteProblem() Content-Type = %v", got)
+			}
+		})
+	}
+}
+
+func TestProblem_UnmarshalJSON(t *testing.T) {
+	data := `{"type":"ErrNotFound","title":"Not found (101)","status":404,"code":101,"user_id":"u-1"}`
+
+	var got Problem
+	if err := json.Unmarshal([]byte(data), &got); err != nil {
+		t.Fatalf("Problem.UnmarshalJSON() error = %v", err)
+	}
+
+	want := Problem{
+		Type:   "ErrNotFound",
+		Title:  "Not found (101)",
+		Status: http.StatusNotFound,
+		Extensions: map[string]interface{}{
+			"code":    float64(101),
+			"user_id": "u-1",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Problem.UnmarshalJSON() = %v, want %v", got, want)
+	}
+}
+
+func TestProblem_Restore(t *testing.T) {
+	tests := []struct {
+		name           string
+		problem        *Problem
+		wantDefinition *errwrap.ErrorDefinition
+		wantCode       int
+		wantMessage    string
+		wantData       errwrap.ErrorData
+		wantFields     []errwrap.FieldViolation
+	}{
+		{
+			name: "success with detail",
+			problem: &Problem{
+				Type:   "https://example.com/errors/ErrNotFound",
+				Title:  "Not found (101)",
+				Detail: "User u-1 not found (101)",
+				Extensions: map[string]interface{}{
+					"code":    float64(101),
+					"user_id": "u-1",
+				},
+			},
+			wantDefinition: errNotFound,
+			wantCode:       101,
+			wantMessage:    "User u-1 not found (101)",
+			wantData:       errwrap.ErrorData{"user_id": "u-1"},
+		},
+		{
+			name: "success with fields",
+			problem: &Problem{
+				Type:  "https://example.com/errors/ErrBadRequest",
+				Title: "Invalid body (100)",
+				Extensions: map[string]interface{}{
+					"code": float64(100),
+					"fields": []interface{}{
+						map[string]interface{}{"field": "name", "code": "required", "message": "Name is required"},
+					},
+				},
+			},
+			wantDefinition: errBadRequest,
+			wantCode:       100,
+			wantMessage:    "Invalid body (100)",
+			wantFields:     []errwrap.FieldViolation{{Field: "name", Code: "required", Message: "Name is required"}},
+		},
+		{
+			name: "success title only",
+			problem: &Problem{
+				Type:  "https://example.com/errors/ErrBadRequest",
+				Title: "Invalid body (100)",
+				Extensions: map[string]interface{}{
+					"code": float64(100),
+				},
+			},
+			wantDefinition: errBadRequest,
+			wantCode:       100,
+			wantMessage:    "Invalid body (100)",
+		},
+		{
+			name: "success unknown type",
+			problem: &Problem{
+				Type:  "about:blank",
+				Title: "Bad gateway",
+			},
+			wantMessage: "Bad gateway",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.problem.Restore(testFactory, "https://example.com/errors/")
+
+			if tt.wantDefinition != nil && !got.Is(tt.wantDefinition) {
+				t.Errorf("Problem.Restore() = %v, want %v", got.CodeString(), tt.wantDefinition.CodeString())
+			}
+			if got.Code() != tt.wantCode {
+				t.Errorf("Problem.Restore() Code() = %v, want %v", got.Code(), tt.wantCode)
+			}
+			if got.ActualError() != tt.wantMessage {
+				t.Errorf("Problem.Restore() ActualError() = %v, want %v", got.ActualError(), tt.wantMessage)
+			}
+			if !reflect.DeepEqual(got.Data(), tt.wantData) {
+				t.Errorf("Problem.Restore() Data() = %v, want %v", got.Data(), tt.wantData)
+			}
+			if !reflect.DeepEqual(got.Fields(), tt.wantFields) {
+				t.Errorf("Problem.Restore() Fields() = %v, want %v", got.Fields(), tt.wantFields)
+			}
+		})
+	}
+}
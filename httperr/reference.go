@@ -0,0 +1,76 @@
+package httperr
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/rapidashorg/errwrap"
+	"github.com/rapidashorg/errwrap/internal/mdtable"
+)
+
+// Reference documents an error definition as written by the writer
+type Reference struct {
+	Code       int                   `json:"code"`
+	CodeString string                `json:"code_string"`
+	Category   errwrap.ErrorCategory `json:"category"`
+	Status     int                   `json:"status"`
+	Masked     bool                  `json:"masked"`
+
+	// Message is the public message of the error definition, see
+	// errwrap.ErrorDefinition.PublicMessage
+	Message string `json:"message"`
+}
+
+// References documents the error definitions, e.g. errwrap.Definitions(),
+// sorted by error code
+func (wr *Writer) References(defs []*errwrap.ErrorDefinition) []Reference {
+	refs := make([]Reference, len(defs))
+	for i, ed := range defs {
+		refs[i] = Reference{
+			Code:       ed.Code(),
+			CodeString: ed.CodeString(),
+			Category:   ed.Category(),
+			Status:     wr.Status(ed.Category()),
+			Masked:     ed.IsMasked(),
+			Message:    ed.PublicMessage(),
+		}
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].Code < refs[j].Code
+	})
+	return refs
+}
+
+// WriteMarkdown writes the references as markdown table
+func WriteMarkdown(w io.Writer, refs []Reference) error {
+	table := mdtable.New(
+		mdtable.Column{Name: "Code", Right: true},
+		mdtable.Column{Name: "Code string"},
+		mdtable.Column{Name: "Category", Right: true},
+		mdtable.Column{Name: "HTTP status"},
+		mdtable.Column{Name: "Masked"},
+		mdtable.Column{Name: "Message"},
+	)
+	for _, ref := range refs {
+		table.Row(
+			strconv.Itoa(ref.Code),
+			"`"+ref.CodeString+"`",
+			strconv.Itoa(int(ref.Category)),
+			fmt.Sprintf("%d %s", ref.Status, http.StatusText(ref.Status)),
+			strconv.FormatBool(ref.Masked),
+			ref.Message,
+		)
+	}
+
+	_, err := io.WriteString(w, table.String())
+	return err
+}
+
+// References documents the error definitions using DefaultWriter
+func References(defs []*errwrap.ErrorDefinition) []Reference {
+	return DefaultWriter.References(defs)
+}
@@ -0,0 +1,45 @@
+package httperr
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rapidashorg/errwrap"
+)
+
+var errConflict = testFactory.NewError(102, "ErrConflict", categoryBadRequest).MessageFormat("User %s | %s already exists")
+
+func TestWriter_References(t *testing.T) {
+	wr := newTestWriter()
+
+	got := wr.References([]*errwrap.ErrorDefinition{errConflict, errNotFound, errBadRequest, ErrUnknown})
+	want := []Reference{
+		{Code: 0, CodeString: "ErrUnknown", Category: CategoryUnknown, Status: http.StatusInternalServerError, Masked: true, Message: errwrap.DefaultMaskMessage},
+		{Code: 100, CodeString: "ErrBadRequest", Category: categoryBadRequest, Status: http.StatusBadRequest},
+		{Code: 101, CodeString: "ErrNotFound", Category: categoryNotFound, Status: http.StatusNotFound, Masked: true, Message: "Not found"},
+		{Code: 102, CodeString: "ErrConflict", Category: categoryBadRequest, Status: http.StatusBadRequest, Message: "User %s | %s already exists"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Writer.References() = %+v, want %+v", got, want)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	refs := newTestWriter().References([]*errwrap.ErrorDefinition{errBadRequest, errNotFound, errConflict})
+
+	var b strings.Builder
+	if err := WriteMarkdown(&b, refs); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+
+	want := "| Code | Code string | Category | HTTP status | Masked | Message |\n" +
+		"| ---: | --- | ---: | --- | --- | --- |\n" +
+		"| 100 | `ErrBadRequest` | 0 | 400 Bad Request | false |  |\n" +
+		"| 101 | `ErrNotFound` | 1 | 404 Not Found | true | Not found |\n" +
+		"| 102 | `ErrConflict` | 0 | 400 Bad Request | false | User %s \\| %s already exists |\n"
+	if got := b.String(); got != want {
+		t.Errorf("WriteMarkdown() =\n%s\nwant\n%s", got, want)
+	}
+}
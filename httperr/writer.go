@@ -0,0 +1,141 @@
+// Package httperr writes errwrap errors as net/http responses, choosing the
+// HTTP status code from the error category.
+package httperr
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/rapidashorg/errwrap"
+)
+
+// CategoryUnknown is the category of ErrUnknown. No status is mapped to this
+// category in default, so the unknown errors are responded with
+// http.StatusInternalServerError.
+const CategoryUnknown errwrap.ErrorCategory = -1
+
+// ErrUnknown is the default fallback error definition, used for errors which
+// don't implement errwrap.ErrorWrapper. The definition is not registered to
+// errwrap.DefaultFactory, so it doesn't collide with application error codes.
+var ErrUnknown = errwrap.NewFactory(errwrap.DefaultConfig()).NewError(0, "ErrUnknown", CategoryUnknown).Masked()
+
+// DefaultWriter is the writer used by package level functions
+var DefaultWriter = NewWriter(ErrUnknown)
+
+// Response is the JSON body written for an error
+type Response struct {
+	Message   string                   `json:"message"`
+	Code      int                      `json:"code"`
+	Fields    []errwrap.FieldViolation `json:"fields,omitempty"`
+	RequestID string                   `json:"request_id,omitempty"`
+}
+
+// Writer writes errors as HTTP responses. The HTTP status code is chosen from
+// the error category, using the statuses set by SetStatus.
+type Writer struct {
+	mu       sync.RWMutex
+	statuses map[errwrap.ErrorCategory]int
+
+	// Fallback is the error definition used to convert errors which don't
+	// implement errwrap.ErrorWrapper, and recovered panics
+	Fallback *errwrap.ErrorDefinition
+
+	// DefaultStatus is the HTTP status code used for categories which don't
+	// have status set
+	DefaultStatus int
+
+	// RequestID returns the request id written in the response. The request id
+	// is omitted if this is nil.
+	RequestID func(r *http.Request) string
+
+	// ProblemTypePrefix is prepended to the error code string to build the
+	// problem type, e.g. "https://example.com/errors/"
+	ProblemTypePrefix string
+
+	// ProblemDataKeys are the error data keys included as extension members
+	// of the problem
+	ProblemDataKeys []string
+
+	// Trusted reports whether the request comes from a trusted client, e.g.
+	// another internal service. The actual error message is only written as
+	// problem detail for trusted clients. No client is trusted if this is nil.
+	Trusted func(r *http.Request) bool
+}
+
+// NewWriter creates writer with given fallback error definition
+func NewWriter(fallback *errwrap.ErrorDefinition) *Writer {
+	return &Writer{
+		statuses:      make(map[errwrap.ErrorCategory]int),
+		Fallback:      fallback,
+		DefaultStatus: http.StatusInternalServerError,
+	}
+}
+
+// SetStatus sets the HTTP status code used for errors with given category
+func (wr *Writer) SetStatus(category errwrap.ErrorCategory, status int) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	wr.statuses[category] = status
+}
+
+// Status returns the HTTP status code used for errors with given category
+func (wr *Writer) Status(category errwrap.ErrorCategory) int {
+	wr.mu.RLock()
+	defer wr.mu.RUnlock()
+
+	if status, ok := wr.statuses[category]; ok {
+		return status
+	}
+	return wr.DefaultStatus
+}
+
+// Convert finds errwrap.ErrorWrapper in the error chain, or wraps the error
+// using the fallback error definition if there is none
+func (wr *Writer) Convert(r *http.Request, err error) errwrap.ErrorWrapper {
+	if erw, ok := errwrap.As(err); ok {
+		return erw
+	}
+	return wr.Fallback.Wrap(r.Context(), err, "%v", err)
+}
+
+// WriteError writes the error as JSON response. The message is masked if the
+// error is masked, so the response is safe to be sent to the client, and is
+// localized to the locale injected into the request context.
+func (wr *Writer) WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	erw := wr.Convert(r, err)
+
+	resp := Response{
+		Message: erw.LocalizedError(r.Context()),
+		Code:    erw.Code(),
+		Fields:  erw.Fields(),
+	}
+	if wr.RequestID != nil {
+		resp.RequestID = wr.RequestID(r)
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(wr.Status(erw.Category()))
+	json.NewEncoder(w).Encode(resp)
+}
+
+// RequestIDFromHeader returns function reading the request id from given
+// request header, to be used as Writer.RequestID
+func RequestIDFromHeader(header string) func(r *http.Request) string {
+	return func(r *http.Request) string {
+		return r.Header.Get(header)
+	}
+}
+
+// SetStatus sets the HTTP status code used by DefaultWriter for errors with
+// given category
+func SetStatus(category errwrap.ErrorCategory, status int) {
+	DefaultWriter.SetStatus(category, status)
+}
+
+// WriteError writes the error as JSON response using DefaultWriter
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	DefaultWriter.WriteError(w, r, err)
+}
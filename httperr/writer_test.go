@@ -0,0 +1,165 @@
+package httperr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rapidashorg/errwrap"
+)
+
+const (
+	categoryBadRequest errwrap.ErrorCategory = iota
+	categoryNotFound
+)
+
+var (
+	testFactory = errwrap.NewFactory(errwrap.DefaultConfig())
+
+	errBadRequest = testFactory.NewError(100, "ErrBadRequest", categoryBadRequest)
+	errNotFound   = testFactory.NewError(101, "ErrNotFound", categoryNotFound).MaskedMessage("Not found")
+)
+
+func newTestWriter() *Writer {
+	wr := NewWriter(ErrUnknown)
+	wr.SetStatus(categoryBadRequest, http.StatusBadRequest)
+	wr.SetStatus(categoryNotFound, http.StatusNotFound)
+	wr.RequestID = RequestIDFromHeader("X-Request-Id")
+	return wr
+}
+
+// newTestMultiError creates multi error whose primary error wrapper is the
+// second one
+func newTestMultiError() *errwrap.MultiError {
+	m := errwrap.NewMultiError(
+		errBadRequest.NewWithoutContext("Invalid name"),
+		errNotFound.NewWithoutContext("User %d not found", 1),
+	)
+	m.Aggregator = errwrap.AggregateBySeverity(categoryNotFound, categoryBadRequest)
+	return m
+}
+
+func TestWriter_Status(t *testing.T) {
+	tests := []struct {
+		name     string
+		category errwrap.ErrorCategory
+		want     int
+	}{
+		{
+			name:     "success",
+			category: categoryNotFound,
+			want:     http.StatusNotFound,
+		},
+		{
+			name:     "success status not set",
+			category: errwrap.ErrorCategory(10),
+			want:     http.StatusInternalServerError,
+		},
+		{
+			name:     "success unknown category",
+			category: CategoryUnknown,
+			want:     http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newTestWriter().Status(tt.category); got != tt.want {
+				t.Errorf("Writer.Status() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_WriteError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		requestID  string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "success",
+			err:        errBadRequest.NewWithoutContext("Invalid body: %s", "missing name"),
+			requestID:  "req-1",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"message":"Invalid body: missing name (100)","code":100,"request_id":"req-1"}` + "\n",
+		},
+		{
+			name: "success with fields",
+			err: errwrap.WithFields(errBadRequest.NewWithoutContext("Invalid body"),
+				errwrap.FieldViolation{Field: "name", Code: "required", Message: "Name is required"}),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"message":"Invalid body (100)","code":100,"fields":[{"field":"name","code":"required","message":"Name is required"}]}` + "\n",
+		},
+		{
+			name:       "success masked and wrapped",
+			err:        fmt.Errorf("handler: %w", errNotFound.NewWithoutContext("User %d not found", 1)),
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"message":"Not found (101)","code":101}` + "\n",
+		},
+		{
+			name:       "success multi error",
+			err:        newTestMultiError(),
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"message":"Invalid name (100); Not found (101)","code":101}` + "\n",
+		},
+		{
+			name: "success multi error with fields",
+			err: errwrap.NewMultiError(
+				errwrap.WithFields(errBadRequest.NewWithoutContext("Invalid name"), errwrap.FieldViolation{Field: "name", Code: "required", Message: "Name is required"}),
+				errwrap.WithFields(errBadRequest.NewWithoutContext("Invalid email"), errwrap.FieldViolation{Field: "email", Code: "invalid", Message: "Email is invalid"}),
+			),
+			wantStatus: http.StatusBadRequest,
+			wantBody: `{"message":"Invalid name (100); Invalid email (100)","code":100,"fields":[` +
+				`{"field":"name","code":"required","message":"Name is required"},` +
+				`{"field":"email","code":"invalid","message":"Email is invalid"}]}` + "\n",
+		},
+		{
+			name:       "success not error wrapper",
+			err:        errors.New("connection refused"),
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"message":"` + errwrap.DefaultMaskMessage + ` (0)","code":0}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.requestID != "" {
+				r.Header.Set("X-Request-Id", tt.requestID)
+			}
+			w := httptest.NewRecorder()
+
+			newTestWriter().WriteError(w, r, tt.err)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Writer.WriteError() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("Writer.WriteError() body = %v, want %v", got, tt.wantBody)
+			}
+			if got := w.Header().Get("Content-Type"); got != "application/json; charset=utf-8" {
+				t.Errorf("Writer.WriteError() Content-Type = %v", got)
+			}
+		})
+	}
+}
+
+func TestWriter_Convert(t *testing.T) {
+	cause := errors.New("connection refused")
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	erw := newTestWriter().Convert(r, cause)
+
+	if !erw.Is(ErrUnknown) {
+		t.Errorf("Writer.Convert() = %v, want %v", erw.CodeString(), ErrUnknown.CodeString())
+	}
+	if !errors.Is(erw, cause) {
+		t.Errorf("Writer.Convert() doesn't wrap the cause error")
+	}
+	if got, want := erw.ActualError(), "connection refused (0)"; got != want {
+		t.Errorf("Writer.Convert() ActualError() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,28 @@
+package fmtargs
+
+import "testing"
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		format string
+		want   int
+	}{
+		{format: "Invalid body", want: 0},
+		{format: "Invalid %s: %d", want: 2},
+		{format: "100%% done %v", want: 1},
+		{format: "%+v %#v %-5s %05d %.2f", want: 5},
+		{format: "%*d %.*f", want: 4},
+		{format: "%[2]s %[1]s", want: 2},
+		{format: "%[3]s", want: 3},
+		{format: "%s %[1]q", want: 1},
+		{format: "%é %s", want: 2},
+		{format: "trailing %", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := Count(tt.format); got != tt.want {
+				t.Errorf("Count() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Package mdtable writes markdown tables, shared by the error definition
+// references of httperr and errwrap-gen.
+package mdtable
+
+import "strings"
+
+// Column is a column of the markdown table
+type Column struct {
+	Name string
+
+	// Right aligns the column to the right, e.g. for numbers
+	Right bool
+}
+
+// Table builds markdown table row by row
+type Table struct {
+	b strings.Builder
+}
+
+// New creates table with the header row of given columns
+func New(columns ...Column) *Table {
+	t := &Table{}
+	names := make([]string, len(columns))
+	aligns := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Name
+		aligns[i] = "---"
+		if column.Right {
+			aligns[i] = "---:"
+		}
+	}
+	t.row(names)
+	t.row(aligns)
+	return t
+}
+
+// Row adds row of cells, each cell is escaped using Cell
+func (t *Table) Row(cells ...string) {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = Cell(cell)
+	}
+	t.row(escaped)
+}
+
+func (t *Table) row(cells []string) {
+	t.b.WriteString("| ")
+	t.b.WriteString(strings.Join(cells, " | "))
+	t.b.WriteString(" |\n")
+}
+
+// String returns the markdown table
+func (t *Table) String() string {
+	return t.b.String()
+}
+
+// Cell escapes the text to be written in a markdown table cell, so "|" doesn't
+// end the cell and line breaks don't end the row
+func Cell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.Join(strings.Fields(text), " ")
+}
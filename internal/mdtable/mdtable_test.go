@@ -0,0 +1,36 @@
+package mdtable
+
+import "testing"
+
+func TestTable(t *testing.T) {
+	table := New(Column{Name: "Code", Right: true}, Column{Name: "Message"})
+	table.Row("100", "Invalid | body")
+	table.Row("101", "")
+
+	want := "| Code | Message |\n" +
+		"| ---: | --- |\n" +
+		"| 100 | Invalid \\| body |\n" +
+		"| 101 |  |\n"
+	if got := table.String(); got != want {
+		t.Errorf("Table.String() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCell(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "Not found", want: "Not found"},
+		{text: "User %s | %s", want: `User %s \| %s`},
+		{text: " Line\n  break\t", want: "Line break"},
+		{text: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := Cell(tt.text); got != tt.want {
+				t.Errorf("Cell() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
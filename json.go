@@ -0,0 +1,280 @@
+package errwrap
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// JSONView defines which fields of the error wrapper are included in JSON
+type JSONView int
+
+const (
+	// JSONViewPublic includes the error message (masked if the error is
+	// masked), error code, and optionally error code string. This view is safe
+	// to be sent to the client.
+	JSONViewPublic JSONView = iota
+
+	// JSONViewDebug includes all data of the error wrapper, including actual
+	// error message, arguments, stack trace, error data, and cause chain. This
+	// view is intended for logs and service-to-service propagation.
+	JSONViewDebug
+)
+
+// jsonPublic is the JSON representation of JSONViewPublic
+type jsonPublic struct {
+	Message    string           `json:"message"`
+	Code       int              `json:"code"`
+	CodeString string           `json:"code_string,omitempty"`
+	Fields     []FieldViolation `json:"fields,omitempty"`
+}
+
+// jsonDebug is the JSON representation of JSONViewDebug. Cause which doesn't
+// implement ErrorWrapper only has message and cause fields filled.
+type jsonDebug struct {
+	Message        string           `json:"message"`
+	Code           int              `json:"code,omitempty"`
+	CodeString     string           `json:"code_string,omitempty"`
+	Category       ErrorCategory    `json:"category,omitempty"`
+	Masked         bool             `json:"masked,omitempty"`
+	ActualMessage  string           `json:"actual_message,omitempty"`
+	RawMessage     string           `json:"raw_message,omitempty"`
+	RawMaskMessage string           `json:"raw_mask_message,omitempty"`
+	Args           []interface{}    `json:"args,omitempty"`
+	Params         Params           `json:"params,omitempty"`
+	StackTrace     []string         `json:"stack_trace,omitempty"`
+	Frames         []Frame          `json:"frames,omitempty"`
+	Data           ErrorData        `json:"data,omitempty"`
+	Fields         []FieldViolation `json:"fields,omitempty"`
+	Cause          *jsonDebug       `json:"cause,omitempty"`
+}
+
+// remoteError is a cause error which doesn't implement ErrorWrapper, rebuilt
+// from JSON
+type remoteError struct {
+	message string
+	cause   error
+}
+
+func (e *remoteError) Error() string {
+	return e.message
+}
+
+func (e *remoteError) Unwrap() error {
+	return e.cause
+}
+
+// MarshalJSON marshals the error wrapper to JSON using given view and
+// DefaultFactory settings
+func MarshalJSON(erw ErrorWrapper, view JSONView) ([]byte, error) {
+	return DefaultFactory.MarshalError(erw, view)
+}
+
+// UnmarshalJSON unmarshals JSON produced by MarshalJSON with the same view
+// back into an ErrorWrapper, resolving the error definition from DefaultFactory
+// registry
+func UnmarshalJSON(data []byte, view JSONView) (ErrorWrapper, error) {
+	return DefaultFactory.UnmarshalError(data, view)
+}
+
+// MarshalError marshals the error wrapper to JSON using given view
+func (f *Factory) MarshalError(erw ErrorWrapper, view JSONView) ([]byte, error) {
+	if view == JSONViewDebug {
+		return json.Marshal(newJSONDebug(erw))
+	}
+
+	v := jsonPublic{
+		Message: erw.Error(),
+		Code:    erw.Code(),
+		Fields:  erw.Fields(),
+	}
+	if f.getConfig().JSONPublicCodeString {
+		v.CodeString = erw.CodeString()
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalError unmarshals JSON produced by MarshalError with the same view
+// back into an ErrorWrapper. The error definition is resolved from the factory
+// registry by the error code string, or by the error code if the code string
+// is not available. The actual error message of JSONViewDebug is restored as
+// rendered by the other service, as the arguments lose their types in JSON.
+func (f *Factory) UnmarshalError(data []byte, view JSONView) (ErrorWrapper, error) {
+	if view == JSONViewDebug {
+		var v jsonDebug
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		return v.errorWrapper(f), nil
+	}
+
+	var v jsonPublic
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+
+	return WithFields(f.RestoreError(v.Code, v.CodeString, v.Message, nil), v.Fields...), nil
+}
+
+// RestoreError rebuilds an ErrorWrapper received from another service using
+// DefaultFactory registry
+func RestoreError(code int, codeString string, message string, data ErrorData) ErrorWrapper {
+	return DefaultFactory.RestoreError(code, codeString, message, data)
+}
+
+// RestoreError rebuilds an ErrorWrapper received from another service, e.g.
+// from an API response. The error definition is resolved from the factory
+// registry by the error code string, or by the error code if the code string
+// is not available, to fill the code, code string, and category. The message
+// has been formatted by the other service, so it is returned as is by Error()
+// and ActualError().
+func (f *Factory) RestoreError(code int, codeString string, message string, data ErrorData) ErrorWrapper {
+	config := f.getConfig()
+	erw := &errorWrapper{
+		code:       code,
+		codeString: codeString,
+
+		message: strings.ReplaceAll(message, "%", "%%"),
+		formatter: func(msg string, erw ErrorWrapper) string {
+			return msg
+		},
+
+		maskMessage:   config.MaskMessage,
+		maskFormatter: config.MaskFormatter,
+
+		data:    data,
+		factory: f,
+	}
+	if ed := f.lookupDefinition(code, codeString); ed != nil {
+		erw.code = ed.code
+		erw.codeString = ed.codeString
+		erw.category = ed.category
+	}
+	return erw
+}
+
+// WithCause returns copy of the error wrapper with given cause, e.g. to keep
+// the transport error of a restored error wrapper for errors.As. Error
+// wrappers not created by this package are returned as is.
+func WithCause(erw ErrorWrapper, cause error) ErrorWrapper {
+	e, ok := erw.(*errorWrapper)
+	if !ok {
+		return erw
+	}
+
+	copied := *e
+	copied.cause = cause
+	return &copied
+}
+
+func (e *errorWrapper) MarshalJSON() ([]byte, error) {
+	f := e.getFactory()
+	return f.MarshalError(e, f.getConfig().JSONView)
+}
+
+func newJSONDebug(err error) *jsonDebug {
+	if err == nil {
+		return nil
+	}
+
+	erw, ok := err.(ErrorWrapper)
+	if !ok {
+		return &jsonDebug{
+			Message: err.Error(),
+			Cause:   newJSONDebug(errors.Unwrap(err)),
+		}
+	}
+
+	return &jsonDebug{
+		Message:        erw.Error(),
+		Code:           erw.Code(),
+		CodeString:     erw.CodeString(),
+		Category:       erw.Category(),
+		Masked:         erw.Masked(),
+		ActualMessage:  erw.ActualError(),
+		RawMessage:     erw.RawMessage(),
+		RawMaskMessage: erw.RawMaskMessage(),
+		Args:           erw.Args(),
+		Params:         erw.Params(),
+		StackTrace:     erw.StackTrace(),
+		Frames:         erw.Frames(),
+		Data:           erw.Data(),
+		Fields:         erw.Fields(),
+		Cause:          newJSONDebug(erw.Unwrap()),
+	}
+}
+
+// cause rebuilds the cause error. The cause is an ErrorWrapper if it has error
+// code or error code string.
+func (v *jsonDebug) cause(f *Factory) error {
+	if v == nil {
+		return nil
+	}
+
+	if v.Code == 0 && v.CodeString == "" {
+		return &remoteError{
+			message: v.Message,
+			cause:   v.Cause.cause(f),
+		}
+	}
+	return v.errorWrapper(f)
+}
+
+func (v *jsonDebug) errorWrapper(f *Factory) *errorWrapper {
+	config := f.getConfig()
+	erw := &errorWrapper{
+		code:       v.Code,
+		codeString: v.CodeString,
+
+		message:   v.RawMessage,
+		category:  v.Category,
+		formatter: config.MessageFormatter,
+
+		isMasked:      v.Masked,
+		maskMessage:   v.RawMaskMessage,
+		maskFormatter: config.MaskFormatter,
+
+		args:       v.Args,
+		stackTrace: v.StackTrace,
+		data:       v.Data,
+		fields:     v.Fields,
+		cause:      v.Cause.cause(f),
+		factory:    f,
+	}
+	if v.ActualMessage != "" {
+		// the arguments lose their types in JSON, e.g. integers are decoded
+		// as float64, so the message rendered by the other service is used
+		erw.restored = &v.ActualMessage
+	}
+	if v.Params != nil {
+		erw.template = parseTemplate(v.RawMessage)
+		erw.params = v.Params
+	}
+	if len(v.Frames) > 0 {
+		erw.stack = newResolvedStack(v.Frames, config.StackTraceMode)
+	}
+	if ed := f.lookupDefinition(v.Code, v.CodeString); ed != nil {
+		if ed.formatter != nil {
+			erw.formatter = *ed.formatter
+		}
+		if ed.maskFormatter != nil {
+			erw.maskFormatter = *ed.maskFormatter
+		}
+	}
+	return erw
+}
+
+// lookupDefinition finds error definition in the factory registry by code
+// string, falling back to code
+func (f *Factory) lookupDefinition(code int, codeString string) *ErrorDefinition {
+	registry := f.Registry()
+	if codeString != "" {
+		if ed, ok := registry.ByCodeString(codeString); ok {
+			return ed
+		}
+	}
+	if ed, ok := registry.ByCode(code); ok {
+		return ed
+	}
+	return nil
+}
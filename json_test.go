@@ -0,0 +1,373 @@
+package errwrap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	type args struct {
+		erw  ErrorWrapper
+		view JSONView
+	}
+	tests := []struct {
+		name           string
+		args           args
+		withCodeString bool
+		want           string
+	}{
+		{
+			name: "success public masked",
+			args: args{
+				erw: &errorWrapper{
+					code:          100,
+					codeString:    "ErrTest",
+					message:       "Test error message: %s",
+					category:      ErrorCategory(1),
+					formatter:     DefaultMessageFormatter,
+					isMasked:      true,
+					maskMessage:   "Test masked error message",
+					maskFormatter: DefaultMaskFormatter,
+					args:          []interface{}{"Foo"},
+				},
+				view: JSONViewPublic,
+			},
+			want: `{"message":"Test masked error message (100)","code":100}`,
+		},
+		{
+			name: "success public with code string",
+			args: args{
+				erw: &errorWrapper{
+					code:       100,
+					codeString: "ErrTest",
+					message:    "Test error message: %s",
+					category:   ErrorCategory(1),
+					formatter:  DefaultMessageFormatter,
+					args:       []interface{}{"Foo"},
+				},
+				view: JSONViewPublic,
+			},
+			withCodeString: true,
+			want:           `{"message":"Test error message: Foo (100)","code":100,"code_string":"ErrTest"}`,
+		},
+		{
+			name: "success debug",
+			args: args{
+				erw: &errorWrapper{
+					code:          100,
+					codeString:    "ErrTest",
+					message:       "Test error message: %s",
+					category:      ErrorCategory(1),
+					formatter:     DefaultMessageFormatter,
+					isMasked:      true,
+					maskMessage:   "Test masked error message",
+					maskFormatter: DefaultMaskFormatter,
+					args:          []interface{}{"Foo"},
+					stackTrace:    []string{"github.com/rapidashorg/errwrap/wrapper.go:1"},
+					data:          ErrorData{"foo": "bar"},
+					cause:         fmt.Errorf("wrapped: %w", errors.New("an error")),
+				},
+				view: JSONViewDebug,
+			},
+			want: `{"message":"Test masked error message (100)","code":100,"code_string":"ErrTest","category":1,` +
+				`"masked":true,"actual_message":"Test error message: Foo (100)","raw_message":"Test error message: %s",` +
+				`"raw_mask_message":"Test masked error message","args":["Foo"],` +
+				`"stack_trace":["github.com/rapidashorg/errwrap/wrapper.go:1"],"data":{"foo":"bar"},` +
+				`"cause":{"message":"wrapped: an error","cause":{"message":"an error"}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setDefaultConfig(t, func(config *Config) {
+				config.JSONPublicCodeString = tt.withCodeString
+			})
+
+			got, err := MarshalJSON(tt.args.erw, tt.args.view)
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("MarshalJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_errorWrapper_MarshalJSON(t *testing.T) {
+	erw := &errorWrapper{
+		code:       100,
+		codeString: "ErrTest",
+		message:    "Test error message",
+		formatter:  DefaultMessageFormatter,
+	}
+
+	got, err := json.Marshal(map[string]interface{}{"error": erw})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	want := `{"error":{"message":"Test error message (100)","code":100}}`
+	if string(got) != want {
+		t.Errorf("json.Marshal() = %s, want %s", got, want)
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	registry := NewRegistry()
+	_ = registry.Register(&ErrorDefinition{code: 102, codeString: "ErrTestRegistered", category: ErrorCategory(2)})
+	setDefaultConfig(t, func(config *Config) {
+		config.Registry = registry
+	})
+
+	type args struct {
+		data string
+		view JSONView
+	}
+	tests := []struct {
+		name            string
+		args            args
+		wantCode        int
+		wantCodeString  string
+		wantCategory    ErrorCategory
+		wantError       string
+		wantActualError string
+		wantData        ErrorData
+		wantCause       error
+		wantErr         bool
+	}{
+		{
+			name: "success public",
+			args: args{
+				data: `{"message":"Discount 100% is invalid (100)","code":100}`,
+				view: JSONViewPublic,
+			},
+			wantCode:        100,
+			wantError:       "Discount 100% is invalid (100)",
+			wantActualError: "Discount 100% is invalid (100)",
+		},
+		{
+			name: "success public resolved from registry",
+			args: args{
+				data: `{"message":"Test error message (102)","code":102}`,
+				view: JSONViewPublic,
+			},
+			wantCode:        102,
+			wantCodeString:  "ErrTestRegistered",
+			wantCategory:    ErrorCategory(2),
+			wantError:       "Test error message (102)",
+			wantActualError: "Test error message (102)",
+		},
+		{
+			name: "success debug",
+			args: args{
+				data: `{"message":"Test masked error message (100)","code":100,"code_string":"ErrTest","category":1,` +
+					`"masked":true,"actual_message":"Test error message: Foo (100)","raw_message":"Test error message: %s",` +
+					`"raw_mask_message":"Test masked error message","args":["Foo"],"data":{"foo":"bar"},` +
+					`"cause":{"message":"wrapped: an error","cause":{"message":"an error"}}}`,
+				view: JSONViewDebug,
+			},
+			wantCode:        100,
+			wantCodeString:  "ErrTest",
+			wantCategory:    ErrorCategory(1),
+			wantError:       "Test masked error message (100)",
+			wantActualError: "Test error message: Foo (100)",
+			wantData:        ErrorData{"foo": "bar"},
+			wantCause: &remoteError{
+				message: "wrapped: an error",
+				cause:   &remoteError{message: "an error"},
+			},
+		},
+		{
+			name: "error invalid JSON",
+			args: args{
+				data: `{"message":`,
+				view: JSONViewPublic,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalJSON([]byte(tt.args.data), tt.args.view)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.Code() != tt.wantCode {
+				t.Errorf("UnmarshalJSON() Code() = %v, want %v", got.Code(), tt.wantCode)
+			}
+			if got.CodeString() != tt.wantCodeString {
+				t.Errorf("UnmarshalJSON() CodeString() = %v, want %v", got.CodeString(), tt.wantCodeString)
+			}
+			if got.Category() != tt.wantCategory {
+				t.Errorf("UnmarshalJSON() Category() = %v, want %v", got.Category(), tt.wantCategory)
+			}
+			if got.Error() != tt.wantError {
+				t.Errorf("UnmarshalJSON() Error() = %v, want %v", got.Error(), tt.wantError)
+			}
+			if got.ActualError() != tt.wantActualError {
+				t.Errorf("UnmarshalJSON() ActualError() = %v, want %v", got.ActualError(), tt.wantActualError)
+			}
+			if !reflect.DeepEqual(got.Data(), tt.wantData) {
+				t.Errorf("UnmarshalJSON() Data() = %v, want %v", got.Data(), tt.wantData)
+			}
+			if !reflect.DeepEqual(got.Unwrap(), tt.wantCause) {
+				t.Errorf("UnmarshalJSON() Unwrap() = %v, want %v", got.Unwrap(), tt.wantCause)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSON_frames(t *testing.T) {
+	erw := NewError(100, "ErrTest", ErrorCategory(1)).NewWithoutContext("Test error message")
+
+	data, err := MarshalJSON(erw, JSONViewDebug)
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+
+	got, err := UnmarshalJSON(data, JSONViewDebug)
+	if err != nil {
+		t.Fatalf("UnmarshalJSON() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got.Frames(), erw.Frames()) {
+		t.Errorf("UnmarshalJSON() Frames() = %v, want %v", got.Frames(), erw.Frames())
+	}
+	if !reflect.DeepEqual(got.StackTrace(), erw.StackTrace()) {
+		t.Errorf("UnmarshalJSON() StackTrace() = %v, want %v", got.StackTrace(), erw.StackTrace())
+	}
+}
+
+func TestUnmarshalJSON_debugRoundTrip(t *testing.T) {
+	f := NewFactory(DefaultConfig())
+	ed := f.NewError(100, "ErrTest", ErrorCategory(1))
+	erw := ed.NewWithoutContext("user %d not found, balance %.2f, %v", 42, 1.5, []string{"a"})
+
+	data, err := f.MarshalError(erw, JSONViewDebug)
+	if err != nil {
+		t.Fatalf("MarshalError() error = %v", err)
+	}
+	got, err := f.UnmarshalError(data, JSONViewDebug)
+	if err != nil {
+		t.Fatalf("UnmarshalError() error = %v", err)
+	}
+
+	want := "user 42 not found, balance 1.50, [a] (100)"
+	if got.ActualError() != want {
+		t.Errorf("UnmarshalError() ActualError() = %v, want %v", got.ActualError(), want)
+	}
+	if got.Error() != want {
+		t.Errorf("UnmarshalError() Error() = %v, want %v", got.Error(), want)
+	}
+	if got.RawMessage() != erw.RawMessage() {
+		t.Errorf("UnmarshalError() RawMessage() = %v, want %v", got.RawMessage(), erw.RawMessage())
+	}
+	if !got.Is(ed) {
+		t.Errorf("UnmarshalError() Is() = false, want true")
+	}
+}
+
+func TestFactory_RestoreError(t *testing.T) {
+	f := NewFactory(DefaultConfig())
+	ed := f.NewError(100, "ErrTest", ErrorCategory(1))
+
+	type args struct {
+		code       int
+		codeString string
+		message    string
+		data       ErrorData
+	}
+	tests := []struct {
+		name           string
+		args           args
+		wantCodeString string
+		wantCategory   ErrorCategory
+		wantIs         bool
+	}{
+		{
+			name: "success resolved by code string",
+			args: args{
+				code:       0,
+				codeString: "ErrTest",
+				message:    "Test error message: 100% (100)",
+				data:       ErrorData{"foo": "bar"},
+			},
+			wantCodeString: "ErrTest",
+			wantCategory:   ErrorCategory(1),
+			wantIs:         true,
+		},
+		{
+			name: "success resolved by code",
+			args: args{
+				code:    100,
+				message: "Test error message: 100% (100)",
+				data:    ErrorData{"foo": "bar"},
+			},
+			wantCodeString: "ErrTest",
+			wantCategory:   ErrorCategory(1),
+			wantIs:         true,
+		},
+		{
+			name: "success not registered",
+			args: args{
+				code:       101,
+				codeString: "ErrTestNotRegistered",
+				message:    "Test error message: 100% (100)",
+			},
+			wantCodeString: "ErrTestNotRegistered",
+			wantCategory:   ErrorCategory(0),
+			wantIs:         false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := f.RestoreError(tt.args.code, tt.args.codeString, tt.args.message, tt.args.data)
+
+			if got.Error() != tt.args.message || got.ActualError() != tt.args.message {
+				t.Errorf("Factory.RestoreError() Error() = %v, want %v", got.Error(), tt.args.message)
+			}
+			if got.CodeString() != tt.wantCodeString {
+				t.Errorf("Factory.RestoreError() CodeString() = %v, want %v", got.CodeString(), tt.wantCodeString)
+			}
+			if got.Category() != tt.wantCategory {
+				t.Errorf("Factory.RestoreError() Category() = %v, want %v", got.Category(), tt.wantCategory)
+			}
+			if !reflect.DeepEqual(got.Data(), tt.args.data) {
+				t.Errorf("Factory.RestoreError() Data() = %v, want %v", got.Data(), tt.args.data)
+			}
+			if got.Is(ed) != tt.wantIs {
+				t.Errorf("Factory.RestoreError() Is() = %v, want %v", got.Is(ed), tt.wantIs)
+			}
+		})
+	}
+}
+
+func TestWithCause(t *testing.T) {
+	f := NewFactory(DefaultConfig())
+	cause := errors.New("Test cause")
+
+	erw := f.RestoreError(100, "ErrTest", "Test error message (100)", nil)
+	got := WithCause(erw, cause)
+
+	if got.Unwrap() != cause || !errors.Is(got, cause) {
+		t.Errorf("WithCause() Unwrap() = %v, want %v", got.Unwrap(), cause)
+	}
+	if erw.Unwrap() != nil {
+		t.Errorf("WithCause() modified the error wrapper, Unwrap() = %v", erw.Unwrap())
+	}
+	if got.ActualError() != erw.ActualError() {
+		t.Errorf("WithCause() ActualError() = %v, want %v", got.ActualError(), erw.ActualError())
+	}
+
+	plain := struct{ ErrorWrapper }{erw}
+	if got := WithCause(plain, cause); got != plain {
+		t.Errorf("WithCause() = %v, want %v", got, plain)
+	}
+}
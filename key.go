@@ -0,0 +1,81 @@
+package errwrap
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// keyTypes records the value type of every key name created using NewKey
+var keyTypes sync.Map // map[string]reflect.Type
+
+// Key is a typed error data key. The value is stored in ErrorData using the
+// key name, so it is serialized like other error data, and read back with
+// compile-time type.
+type Key[T any] struct {
+	name string
+}
+
+// NewKey creates typed error data key with given name. Use qualified names,
+// e.g. "order.id" instead of "id", to avoid collision with other layers.
+// NewKey panics if the name has been used by a key with different value type.
+func NewKey[T any](name string) Key[T] {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if existing, loaded := keyTypes.LoadOrStore(name, typ); loaded && existing != typ {
+		panic(fmt.Sprintf("errwrap: error data key %q is used with types %v and %v", name, existing, typ))
+	}
+	return Key[T]{name: name}
+}
+
+// Name returns the key name, which is the key in ErrorData
+func (k Key[T]) Name() string {
+	return k.name
+}
+
+// Data returns ErrorData containing the value, to be combined with other
+// error data using InjectErrorData
+func (k Key[T]) Data(value T) ErrorData {
+	return ErrorData{k.name: value}
+}
+
+// Inject injects the value into context, same as InjectErrorData
+func (k Key[T]) Inject(ctx context.Context, value T) context.Context {
+	return InjectErrorData(ctx, k.Data(value))
+}
+
+// FromContext returns the value injected into context. Returns false if the
+// value is not injected, or has different type.
+func (k Key[T]) FromContext(ctx context.Context) (T, bool) {
+	for w := getErrorDataWrapper(ctx); w != nil; w = w.parent {
+		if v, ok := w.data[k.name]; ok {
+			return k.cast(v)
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// FromError returns the value from the data of the first ErrorWrapper in the
+// error chain. The value is read before redaction. Returns false if the value
+// doesn't exist, or has different type.
+func (k Key[T]) FromError(err error) (T, bool) {
+	erw, ok := As(err)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+
+	data := erw.Data()
+	if e, ok := erw.(*errorWrapper); ok {
+		data = e.data
+	}
+	return k.cast(data[k.name])
+}
+
+// cast asserts the value type
+func (k Key[T]) cast(v interface{}) (T, bool) {
+	value, ok := v.(T)
+	return value, ok
+}
@@ -0,0 +1,60 @@
+package errwrap
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	keyUserID := NewKey[int64]("test.user_id")
+	keyOrderID := NewKey[string]("test.order_id")
+
+	ctx := keyUserID.Inject(context.Background(), 1)
+	ctx = InjectErrorData(ctx, keyOrderID.Data("o-1"))
+	ctx = InjectErrorData(ctx, ErrorData{"test.user_id_untyped": 2})
+
+	if got, ok := keyUserID.FromContext(ctx); !ok || got != 1 {
+		t.Errorf("Key.FromContext() = %v, %v, want 1, true", got, ok)
+	}
+
+	erw := NewError(100, "ErrTest", ErrorCategory(1)).New(ctx, "Test error message")
+	wrapped := fmt.Errorf("handler: %w", erw)
+
+	if got, ok := keyOrderID.FromError(wrapped); !ok || got != "o-1" {
+		t.Errorf("Key.FromError() = %v, %v, want o-1, true", got, ok)
+	}
+	if got, ok := NewKey[string]("test.missing").FromError(wrapped); ok || got != "" {
+		t.Errorf("Key.FromError() = %v, %v, want empty, false", got, ok)
+	}
+
+	wantData := ErrorData{"test.user_id": int64(1), "test.order_id": "o-1", "test.user_id_untyped": 2}
+	if !reflect.DeepEqual(erw.Data(), wantData) {
+		t.Errorf("Data() = %v, want %v", erw.Data(), wantData)
+	}
+}
+
+func TestKey_FromContext_typeMismatch(t *testing.T) {
+	key := NewKey[int]("test.mismatch")
+	ctx := InjectErrorData(context.Background(), ErrorData{"test.mismatch": "1"})
+
+	if got, ok := key.FromContext(ctx); ok || got != 0 {
+		t.Errorf("Key.FromContext() = %v, %v, want 0, false", got, ok)
+	}
+	if got, ok := key.FromContext(context.Background()); ok || got != 0 {
+		t.Errorf("Key.FromContext() = %v, %v, want 0, false", got, ok)
+	}
+}
+
+func TestNewKey_duplicate(t *testing.T) {
+	NewKey[string]("test.duplicate")
+	NewKey[string]("test.duplicate")
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewKey() with different type doesn't panic")
+		}
+	}()
+	NewKey[int]("test.duplicate")
+}
@@ -0,0 +1,321 @@
+package errwrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// Aggregator chooses the error wrapper whose code and category represent the
+// multi error. errs has at least one error wrapper.
+type Aggregator func(errs []ErrorWrapper) ErrorWrapper
+
+// AggregateFirst chooses the first error wrapper
+func AggregateFirst(errs []ErrorWrapper) ErrorWrapper {
+	return errs[0]
+}
+
+// AggregateBySeverity returns aggregator choosing the first error wrapper with
+// the most severe category. Categories are given from the most severe, and
+// categories which are not given are the least severe.
+func AggregateBySeverity(categories ...ErrorCategory) Aggregator {
+	severity := make(map[ErrorCategory]int, len(categories))
+	for i, category := range categories {
+		severity[category] = len(categories) - i
+	}
+
+	return func(errs []ErrorWrapper) ErrorWrapper {
+		chosen := errs[0]
+		for _, erw := range errs[1:] {
+			if severity[erw.Category()] > severity[chosen.Category()] {
+				chosen = erw
+			}
+		}
+		return chosen
+	}
+}
+
+// MultiError aggregates multiple error wrappers, e.g. validation errors of a
+// request. The zero value is ready to use. The code and category of the multi
+// error are taken from the error wrapper chosen by the aggregator. Methods
+// other than Append can be called on nil multi error.
+//
+// MultiError implements Unwrap() []error, so errors.Is and errors.As match any
+// of the error wrappers.
+type MultiError struct {
+	errs []ErrorWrapper
+
+	// Aggregator chooses the error wrapper representing the multi error.
+	// AggregateFirst is used if this is nil.
+	Aggregator Aggregator
+}
+
+// NewMultiError creates multi error containing given error wrappers
+func NewMultiError(errs ...ErrorWrapper) *MultiError {
+	m := &MultiError{}
+	for _, erw := range errs {
+		m.Append(erw)
+	}
+	return m
+}
+
+// Append adds the error wrapper, nil error wrapper is ignored
+func (m *MultiError) Append(erw ErrorWrapper) {
+	if erw == nil {
+		return
+	}
+	m.errs = append(m.errs, erw)
+}
+
+// Errors returns copy of the error wrappers
+func (m *MultiError) Errors() []ErrorWrapper {
+	errs := m.wrappers()
+	if errs == nil {
+		return nil
+	}
+	return append([]ErrorWrapper(nil), errs...)
+}
+
+// wrappers returns the error wrappers, or nil if the multi error is nil, so
+// the methods can be called on nil multi error
+func (m *MultiError) wrappers() []ErrorWrapper {
+	if m == nil {
+		return nil
+	}
+	return m.errs
+}
+
+// Len returns the number of error wrappers
+func (m *MultiError) Len() int {
+	return len(m.wrappers())
+}
+
+// ErrorOrNil returns nil if the multi error is empty, so it can be returned as
+// error directly
+func (m *MultiError) ErrorOrNil() error {
+	if m == nil || len(m.errs) == 0 {
+		return nil
+	}
+	return m
+}
+
+// Primary returns the error wrapper representing the multi error, or nil if
+// the multi error is empty
+func (m *MultiError) Primary() ErrorWrapper {
+	if m.Len() == 0 {
+		return nil
+	}
+	if m.Aggregator == nil {
+		return AggregateFirst(m.errs)
+	}
+	return m.Aggregator(m.errs)
+}
+
+// Code returns the error code of the primary error wrapper
+func (m *MultiError) Code() int {
+	if primary := m.Primary(); primary != nil {
+		return primary.Code()
+	}
+	return 0
+}
+
+// CodeString returns the error code string of the primary error wrapper
+func (m *MultiError) CodeString() string {
+	if primary := m.Primary(); primary != nil {
+		return primary.CodeString()
+	}
+	return ""
+}
+
+// Category returns the error category of the primary error wrapper
+func (m *MultiError) Category() ErrorCategory {
+	if primary := m.Primary(); primary != nil {
+		return primary.Category()
+	}
+	return 0
+}
+
+// Error returns the messages of all error wrappers, masked if the error
+// wrapper is masked
+func (m *MultiError) Error() string {
+	return m.join(ErrorWrapper.Error)
+}
+
+// ActualError returns the actual messages of all error wrappers
+func (m *MultiError) ActualError() string {
+	return m.join(ErrorWrapper.ActualError)
+}
+
+// LocalizedError returns the localized messages of all error wrappers
+func (m *MultiError) LocalizedError(ctx context.Context) string {
+	return m.join(func(erw ErrorWrapper) string {
+		return erw.LocalizedError(ctx)
+	})
+}
+
+// UnredactedError returns the unredacted actual messages of all error
+// wrappers, it must not be logged or sent to the client
+func (m *MultiError) UnredactedError() string {
+	return m.join(ErrorWrapper.UnredactedError)
+}
+
+// join joins the messages of all error wrappers
+func (m *MultiError) join(message func(ErrorWrapper) string) string {
+	errs := m.wrappers()
+	messages := make([]string, len(errs))
+	for i, erw := range errs {
+		messages[i] = message(erw)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the error wrappers, used by errors.Is and errors.As
+func (m *MultiError) Unwrap() []error {
+	errs := make([]error, m.Len())
+	for i, erw := range m.wrappers() {
+		errs[i] = erw
+	}
+	return errs
+}
+
+// ErrorWrapper returns ErrorWrapper representing the multi error, or nil if
+// the multi error is empty. Code, code string, category, and the rest of
+// single error details are taken from the primary error wrapper, while the
+// messages and field violations are aggregated from all error wrappers. This
+// is what As returns when it finds the multi error in the error chain, so the
+// multi error is written as a single response by httperr.
+func (m *MultiError) ErrorWrapper() ErrorWrapper {
+	if m.Len() == 0 {
+		return nil
+	}
+	return &multiErrorWrapper{MultiError: m}
+}
+
+// multiErrorWrapper adapts MultiError into ErrorWrapper, its Unwrap returns
+// the multi error, so errors.Is and errors.As still match any error wrapper
+type multiErrorWrapper struct {
+	*MultiError
+}
+
+func (w *multiErrorWrapper) Masked() bool {
+	return w.Primary().Masked()
+}
+
+func (w *multiErrorWrapper) RawMessage() string {
+	return w.Primary().RawMessage()
+}
+
+func (w *multiErrorWrapper) RawMaskMessage() string {
+	return w.Primary().RawMaskMessage()
+}
+
+func (w *multiErrorWrapper) Args() []interface{} {
+	return w.Primary().Args()
+}
+
+func (w *multiErrorWrapper) Params() Params {
+	return w.Primary().Params()
+}
+
+func (w *multiErrorWrapper) StackTrace() []string {
+	return w.Primary().StackTrace()
+}
+
+func (w *multiErrorWrapper) Frames() []Frame {
+	return w.Primary().Frames()
+}
+
+func (w *multiErrorWrapper) Data() ErrorData {
+	return w.Primary().Data()
+}
+
+// Is reports whether any of the error wrappers is created from the error
+// definition
+func (w *multiErrorWrapper) Is(err error) bool {
+	for _, erw := range w.errs {
+		if erw.Is(err) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *multiErrorWrapper) Unwrap() error {
+	return w.MultiError
+}
+
+// jsonMulti is the JSON of multi error, the error wrappers are marshalled
+// using their own JSON view, or the public view if they don't implement
+// json.Marshaler
+type jsonMulti struct {
+	Message string        `json:"message"`
+	Code    int           `json:"code"`
+	Errors  []interface{} `json:"errors"`
+}
+
+// MarshalJSON marshals the multi error with masked message, code, and each
+// error wrapper
+func (m *MultiError) MarshalJSON() ([]byte, error) {
+	errs := make([]interface{}, m.Len())
+	for i, erw := range m.wrappers() {
+		errs[i] = erw
+		if _, ok := erw.(json.Marshaler); !ok {
+			errs[i] = jsonPublic{Message: erw.Error(), Code: erw.Code(), Fields: erw.Fields()}
+		}
+	}
+	return json.Marshal(jsonMulti{
+		Message: m.Error(),
+		Code:    m.Code(),
+		Errors:  errs,
+	})
+}
+
+// Format implements fmt.Formatter. %s and %v print the messages, %q prints the
+// quoted messages, and %+v prints each error wrapper using %+v.
+func (m *MultiError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%d errors occurred:", m.Len())
+			for _, erw := range m.wrappers() {
+				io.WriteString(s, "\n* ")
+				io.WriteString(s, strings.ReplaceAll(fmt.Sprintf("%+v", erw), "\n", "\n  "))
+			}
+			return
+		}
+		io.WriteString(s, m.Error())
+
+	case 's':
+		io.WriteString(s, m.Error())
+
+	case 'q':
+		fmt.Fprintf(s, "%q", m.Error())
+
+	default:
+		fmt.Fprintf(s, "%%!%c(%s)", verb, m.Error())
+	}
+}
+
+// LogValue implements slog.LogValuer, logging the multi error code, code
+// string, category, and each error wrapper
+func (m *MultiError) LogValue() slog.Value {
+	return m.logValue(true)
+}
+
+func (m *MultiError) logValue(withStackTrace bool) slog.Value {
+	errs := make([]slog.Attr, m.Len())
+	for i, erw := range m.wrappers() {
+		errs[i] = slog.Attr{Key: strconv.Itoa(i), Value: errorWrapperLogValue(erw, withStackTrace)}
+	}
+
+	return slog.GroupValue(
+		slog.Int("code", m.Code()),
+		slog.String("code_string", m.CodeString()),
+		slog.Int("category", int(m.Category())),
+		slog.Attr{Key: "errors", Value: slog.GroupValue(errs...)},
+	)
+}
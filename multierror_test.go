@@ -0,0 +1,224 @@
+package errwrap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func newTestMultiError() (*MultiError, *ErrorDefinition, *ErrorDefinition) {
+	f := NewFactory(DefaultConfig())
+	edBadRequest := f.NewError(100, "ErrBadRequest", ErrorCategory(1))
+	edInternal := f.NewError(101, "ErrInternal", ErrorCategory(2)).Masked()
+
+	m := NewMultiError(
+		edBadRequest.NewWithoutContext("Invalid name"),
+		nil,
+		edInternal.NewWithoutContext("Invalid state"),
+		edBadRequest.NewWithoutContext("Invalid email"),
+	)
+	return m, edBadRequest, edInternal
+}
+
+func TestMultiError(t *testing.T) {
+	m, edBadRequest, edInternal := newTestMultiError()
+
+	if m.Len() != 3 {
+		t.Errorf("MultiError.Len() = %v, want 3", m.Len())
+	}
+	if got, want := m.Error(), "Invalid name (100); "+DefaultMaskMessage+" (101); Invalid email (100)"; got != want {
+		t.Errorf("MultiError.Error() = %v, want %v", got, want)
+	}
+	if got, want := m.ActualError(), "Invalid name (100); Invalid state (101); Invalid email (100)"; got != want {
+		t.Errorf("MultiError.ActualError() = %v, want %v", got, want)
+	}
+
+	err := fmt.Errorf("validate: %w", m.ErrorOrNil())
+	if !errors.Is(err, edBadRequest) || !errors.Is(err, edInternal) {
+		t.Errorf("errors.Is() doesn't match the error wrappers")
+	}
+	var got *MultiError
+	if !errors.As(err, &got) || got != m {
+		t.Errorf("errors.As() = %v, want %v", got, m)
+	}
+
+	var empty MultiError
+	if empty.ErrorOrNil() != nil {
+		t.Errorf("MultiError.ErrorOrNil() = %v, want nil", empty.ErrorOrNil())
+	}
+	if empty.Code() != 0 || empty.CodeString() != "" {
+		t.Errorf("MultiError.Code() = %v, CodeString() = %v, want empty", empty.Code(), empty.CodeString())
+	}
+}
+
+func TestMultiError_nil(t *testing.T) {
+	var m *MultiError
+
+	if m.Len() != 0 || m.Errors() != nil || m.Primary() != nil || m.ErrorWrapper() != nil || m.ErrorOrNil() != nil {
+		t.Errorf("MultiError methods of nil multi error aren't empty")
+	}
+	if m.Error() != "" || m.ActualError() != "" || m.UnredactedError() != "" || m.LocalizedError(context.Background()) != "" {
+		t.Errorf("MultiError.Error() of nil multi error isn't empty")
+	}
+	if m.Code() != 0 || m.CodeString() != "" || m.Category() != 0 || m.Fields() != nil || len(m.Unwrap()) != 0 {
+		t.Errorf("MultiError.Code() of nil multi error isn't empty")
+	}
+	if got := fmt.Sprintf("%+v", m); got != "0 errors occurred:" {
+		t.Errorf("MultiError.Format() = %v", got)
+	}
+	m.LogValue()
+}
+
+func TestMultiError_Errors(t *testing.T) {
+	m, _, _ := newTestMultiError()
+
+	errs := m.Errors()
+	errs[0] = nil
+	if m.Errors()[0] == nil {
+		t.Errorf("MultiError.Errors() returns the internal slice")
+	}
+}
+
+func TestMultiError_Aggregator(t *testing.T) {
+	tests := []struct {
+		name         string
+		aggregator   Aggregator
+		wantCode     int
+		wantCategory ErrorCategory
+	}{
+		{
+			name:         "success default",
+			wantCode:     100,
+			wantCategory: ErrorCategory(1),
+		},
+		{
+			name:         "success by severity",
+			aggregator:   AggregateBySeverity(ErrorCategory(2), ErrorCategory(1)),
+			wantCode:     101,
+			wantCategory: ErrorCategory(2),
+		},
+		{
+			name:         "success by severity category not given",
+			aggregator:   AggregateBySeverity(ErrorCategory(1)),
+			wantCode:     100,
+			wantCategory: ErrorCategory(1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _, _ := newTestMultiError()
+			m.Aggregator = tt.aggregator
+
+			if m.Code() != tt.wantCode {
+				t.Errorf("MultiError.Code() = %v, want %v", m.Code(), tt.wantCode)
+			}
+			if m.Category() != tt.wantCategory {
+				t.Errorf("MultiError.Category() = %v, want %v", m.Category(), tt.wantCategory)
+			}
+		})
+	}
+}
+
+func TestMultiError_ErrorWrapper(t *testing.T) {
+	m, edBadRequest, edInternal := newTestMultiError()
+	m.Aggregator = AggregateBySeverity(ErrorCategory(2), ErrorCategory(1))
+
+	if (&MultiError{}).ErrorWrapper() != nil {
+		t.Errorf("MultiError.ErrorWrapper() of empty multi error is not nil")
+	}
+
+	got, ok := As(fmt.Errorf("handling request: %w", m))
+	if !ok {
+		t.Fatalf("As() ok = false, want true")
+	}
+	if got.Code() != 101 || got.CodeString() != "ErrInternal" || got.Category() != ErrorCategory(2) {
+		t.Errorf("As() = %d %s %d, want the primary error wrapper code", got.Code(), got.CodeString(), got.Category())
+	}
+	if got.Error() != m.Error() || got.ActualError() != m.ActualError() {
+		t.Errorf("As() Error() = %v, want %v", got.Error(), m.Error())
+	}
+	if !got.Masked() {
+		t.Errorf("As() Masked() = false, want the primary error wrapper masked")
+	}
+	if !got.Is(edBadRequest) || !errors.Is(got, edInternal) {
+		t.Errorf("As() doesn't match the error definitions of the error wrappers")
+	}
+
+	var target *MultiError
+	if !errors.As(got, &target) || target != m {
+		t.Errorf("errors.As() = %v, want the multi error", target)
+	}
+
+	// error wrappers found before the multi error are returned as is
+	erw := edBadRequest.WrapWithoutContext(m, "Invalid request")
+	if got, _ := As(erw); got != erw {
+		t.Errorf("As() = %v, want %v", got, erw)
+	}
+}
+
+func TestMultiError_MarshalJSON(t *testing.T) {
+	m, _, _ := newTestMultiError()
+
+	got, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("MultiError.MarshalJSON() error = %v", err)
+	}
+
+	want := `{"message":"Invalid name (100); ` + DefaultMaskMessage + ` (101); Invalid email (100)","code":100,"errors":[` +
+		`{"message":"Invalid name (100)","code":100},` +
+		`{"message":"` + DefaultMaskMessage + ` (101)","code":101},` +
+		`{"message":"Invalid email (100)","code":100}]}`
+	if string(got) != want {
+		t.Errorf("MultiError.MarshalJSON() = %s, want %s", got, want)
+	}
+
+	if got, _ := json.Marshal(&MultiError{}); string(got) != `{"message":"","code":0,"errors":[]}` {
+		t.Errorf("MultiError.MarshalJSON() = %s", got)
+	}
+
+	// error wrappers not implementing json.Marshaler use the public view
+	plain := struct{ ErrorWrapper }{m.Errors()[0]}
+	if got, _ := json.Marshal(NewMultiError(plain)); string(got) != `{"message":"Invalid name (100)","code":100,"errors":[{"message":"Invalid name (100)","code":100}]}` {
+		t.Errorf("MultiError.MarshalJSON() = %s", got)
+	}
+}
+
+func TestMultiError_Format(t *testing.T) {
+	m, _, _ := newTestMultiError()
+
+	got := fmt.Sprintf("%+v", m)
+	for _, want := range []string{"3 errors occurred:", "\n* Invalid name (100)\n  code string: ErrBadRequest", "\n* Invalid state (101)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("MultiError.Format() = %v, want containing %q", got, want)
+		}
+	}
+	if got := fmt.Sprintf("%v", m); got != m.Error() {
+		t.Errorf("MultiError.Format() = %v, want %v", got, m.Error())
+	}
+}
+
+func TestMultiError_slog(t *testing.T) {
+	m, _, _ := newTestMultiError()
+
+	var buf bytes.Buffer
+	logger := slog.New(NewSlogHandler(slog.NewJSONHandler(&buf, nil), nil))
+	logger.Log(context.Background(), slog.LevelError, "failed", "err", fmt.Errorf("validate: %w", m))
+
+	var got struct {
+		Err struct {
+			Code   int                               `json:"code"`
+			Errors map[string]map[string]interface{} `json:"errors"`
+		} `json:"err"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v, log = %s", err, buf.String())
+	}
+	if got.Err.Code != 100 || len(got.Err.Errors) != 3 || got.Err.Errors["1"]["message"] != "Invalid state (101)" {
+		t.Errorf("SlogHandler.Handle() err = %+v", got.Err)
+	}
+}
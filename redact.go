@@ -0,0 +1,169 @@
+package errwrap
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// DefaultRedactPlaceholder is the default placeholder replacing redacted
+// values
+const DefaultRedactPlaceholder = "[REDACTED]"
+
+// Redactable is implemented by values which contain sensitive data, e.g. email
+// addresses or tokens. Redact returns the value rendered in logs and JSON
+// instead, when the value is used as error data or error argument.
+type Redactable interface {
+	Redact() interface{}
+}
+
+// secret is error argument marked as secret using Secret
+type secret struct {
+	value interface{}
+}
+
+// Secret marks the error argument as secret. Secret arguments are replaced by
+// the redact placeholder in rendered messages, args, logs, and JSON, and are
+// only printed by ErrorWrapper.UnredactedError.
+func Secret(value interface{}) interface{} {
+	return secret{value: value}
+}
+
+// Format implements fmt.Formatter, so secrets printed outside of error
+// wrappers are redacted too, using the redact placeholder of DefaultFactory
+// settings. The placeholder is printed for every verb.
+func (s secret) Format(f fmt.State, verb rune) {
+	fmt.Fprint(f, DefaultFactory.getConfig().RedactPlaceholder)
+}
+
+// MarshalJSON marshals the secret as the redact placeholder of DefaultFactory
+// settings
+func (s secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(DefaultFactory.getConfig().RedactPlaceholder)
+}
+
+// redactedArg renders the redacted value of an argument in the message. The
+// redacted value is printed using the format verb only if it has the same type
+// as the argument, e.g. a secret int printed using %d is rendered as the
+// placeholder instead of %!d(string=[REDACTED]).
+type redactedArg struct {
+	arg   interface{}
+	value interface{}
+}
+
+func (a redactedArg) Format(f fmt.State, verb rune) {
+	if reflect.TypeOf(a.value) == reflect.TypeOf(a.arg) {
+		fmt.Fprintf(f, fmt.FormatString(f, verb), a.value)
+		return
+	}
+	fmt.Fprint(f, a.value)
+}
+
+// redactor redacts error data and arguments using the factory settings
+type redactor struct {
+	keys        []string
+	placeholder string
+}
+
+func newRedactor(config *Config) redactor {
+	return redactor{keys: config.RedactKeys, placeholder: config.RedactPlaceholder}
+}
+
+// matchKey reports whether the error data key matches any of the redact key
+// patterns
+func (r redactor) matchKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range r.keys {
+		if ok, _ := path.Match(strings.ToLower(pattern), key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// value returns the redacted value, and whether the value is redacted
+func (r redactor) value(v interface{}) (interface{}, bool) {
+	switch v := v.(type) {
+	case secret:
+		return r.placeholder, true
+	case Redactable:
+		return v.Redact(), true
+	}
+	return v, false
+}
+
+// args returns the redacted arguments. The arguments are returned as is if
+// none of them is redacted.
+func (r redactor) args(args []interface{}) []interface{} {
+	return replaceArgs(args, r.value)
+}
+
+// renderArgs returns the arguments used to render the message, redacted
+// arguments are replaced by redactedArg, so they are rendered for every verb
+func (r redactor) renderArgs(args []interface{}) []interface{} {
+	return replaceArgs(args, func(arg interface{}) (interface{}, bool) {
+		v, ok := r.value(arg)
+		if !ok {
+			return arg, false
+		}
+		return redactedArg{arg: arg, value: v}, true
+	})
+}
+
+// data returns the redacted error data. The error data is returned as is if
+// none of the values is redacted.
+func (r redactor) data(data ErrorData) ErrorData {
+	var redacted ErrorData
+	for k, v := range data {
+		rv, ok := r.value(v)
+		if r.matchKey(k) {
+			rv, ok = r.placeholder, true
+		}
+		if !ok {
+			continue
+		}
+		if redacted == nil {
+			redacted = make(ErrorData, len(data))
+			for k, v := range data {
+				redacted[k] = v
+			}
+		}
+		redacted[k] = rv
+	}
+	if redacted == nil {
+		return data
+	}
+	return redacted
+}
+
+// unredactedArgs returns the arguments with secret values unwrapped
+func unredactedArgs(args []interface{}) []interface{} {
+	return replaceArgs(args, func(arg interface{}) (interface{}, bool) {
+		s, ok := arg.(secret)
+		return s.value, ok
+	})
+}
+
+// replaceArgs replaces the arguments using fn, which reports whether the
+// argument is replaced. The arguments are returned as is if none of them is
+// replaced.
+func replaceArgs(args []interface{}, fn func(arg interface{}) (interface{}, bool)) []interface{} {
+	var replaced []interface{}
+	for i, arg := range args {
+		v, ok := fn(arg)
+		if !ok {
+			continue
+		}
+		if replaced == nil {
+			replaced = make([]interface{}, len(args))
+			copy(replaced, args)
+		}
+		replaced[i] = v
+	}
+	if replaced == nil {
+		return args
+	}
+	return replaced
+}
@@ -0,0 +1,159 @@
+package errwrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testEmail string
+
+func (e testEmail) Redact() interface{} {
+	s := string(e)
+	if i := strings.Index(s, "@"); i > 0 {
+		return s[:1] + "***" + s[i:]
+	}
+	return DefaultRedactPlaceholder
+}
+
+func newTestRedactFactory() *Factory {
+	config := DefaultConfig()
+	config.RedactKeys = []string{"password", "*Token*"}
+	config.JSONView = JSONViewDebug
+	return NewFactory(config)
+}
+
+func TestErrorWrapper_redaction(t *testing.T) {
+	ed := newTestRedactFactory().NewError(100, "ErrTest", ErrorCategory(1))
+	ctx := InjectErrorData(context.Background(), ErrorData{
+		"user_id":      1,
+		"password":     "hunter2",
+		"access_token": "abc",
+		"email":        testEmail("john@example.com"),
+	})
+	erw := ed.New(ctx, "Login failed for %s with token %s", testEmail("john@example.com"), Secret("abc"))
+
+	if got, want := erw.Error(), "Login failed for j***@example.com with token [REDACTED] (100)"; got != want {
+		t.Errorf("Error() = %v, want %v", got, want)
+	}
+	if got, want := erw.ActualError(), "Login failed for j***@example.com with token [REDACTED] (100)"; got != want {
+		t.Errorf("ActualError() = %v, want %v", got, want)
+	}
+	if got, want := erw.UnredactedError(), "Login failed for john@example.com with token abc (100)"; got != want {
+		t.Errorf("UnredactedError() = %v, want %v", got, want)
+	}
+
+	wantArgs := []interface{}{"j***@example.com", "[REDACTED]"}
+	if got := erw.Args(); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("Args() = %v, want %v", got, wantArgs)
+	}
+
+	wantData := ErrorData{
+		"user_id":      1,
+		"password":     "[REDACTED]",
+		"access_token": "[REDACTED]",
+		"email":        "j***@example.com",
+	}
+	if got := erw.Data(); !reflect.DeepEqual(got, wantData) {
+		t.Errorf("Data() = %v, want %v", got, wantData)
+	}
+
+	for name, got := range map[string]string{
+		"%+v":  fmt.Sprintf("%+v", erw),
+		"%#v":  fmt.Sprintf("%#v", erw),
+		"JSON": func() string { b, _ := json.Marshal(erw); return string(b) }(),
+	} {
+		for _, secret := range []string{"hunter2", "abc", "john@"} {
+			if strings.Contains(got, secret) {
+				t.Errorf("%s contains %q: %s", name, secret, got)
+			}
+		}
+	}
+}
+
+func TestConvert_keepsUnredactedData(t *testing.T) {
+	f := newTestRedactFactory()
+	ctx := InjectErrorData(context.Background(), ErrorData{"password": "hunter2"})
+	erw := f.NewError(100, "ErrTest", ErrorCategory(1)).New(ctx, "Token %s", Secret("abc"))
+
+	got := Convert(context.Background(), erw, f.NewError(101, "ErrTestNew", ErrorCategory(1))).(*errorWrapper)
+
+	if !reflect.DeepEqual(got.data, ErrorData{"password": "hunter2"}) {
+		t.Errorf("Convert() data = %v", got.data)
+	}
+	if want := "Token abc (101)"; got.UnredactedError() != want {
+		t.Errorf("Convert() UnredactedError() = %v, want %v", got.UnredactedError(), want)
+	}
+}
+
+type testPIN int
+
+func (p testPIN) Redact() interface{} {
+	return "****"
+}
+
+type testAmount int
+
+func (a testAmount) Redact() interface{} {
+	return testAmount(0)
+}
+
+func TestErrorWrapper_redactionVerbs(t *testing.T) {
+	config := DefaultConfig()
+	config.RedactPlaceholder = "***"
+	ed := NewFactory(config).NewError(100, "ErrTest", ErrorCategory(1))
+
+	erw := ed.NewWithoutContext("pin %d invalid, old pin %04d, amount %03d", Secret(1234), testPIN(5678), testAmount(42))
+	if got, want := erw.ActualError(), "pin *** invalid, old pin ****, amount 000 (100)"; got != want {
+		t.Errorf("ActualError() = %v, want %v", got, want)
+	}
+	if got, want := erw.UnredactedError(), "pin 1234 invalid, old pin 5678, amount 042 (100)"; got != want {
+		t.Errorf("UnredactedError() = %v, want %v", got, want)
+	}
+
+	wantArgs := []interface{}{"***", "****", testAmount(0)}
+	if got := erw.Args(); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("Args() = %v, want %v", got, wantArgs)
+	}
+}
+
+func TestSecret_Format(t *testing.T) {
+	if got := fmt.Sprintf("%v %s %d", Secret("abc"), Secret("abc"), Secret(1)); got != "[REDACTED] [REDACTED] [REDACTED]" {
+		t.Errorf("Secret() formatted = %v", got)
+	}
+
+	setDefaultConfig(t, func(config *Config) {
+		config.RedactPlaceholder = "***"
+	})
+	if got := fmt.Sprintf("%d", Secret(1)); got != "***" {
+		t.Errorf("Secret() formatted with placeholder = %v", got)
+	}
+	if got, _ := json.Marshal(Secret(1)); string(got) != `"***"` {
+		t.Errorf("Secret() JSON with placeholder = %s", got)
+	}
+}
+
+func Test_redactor_matchKey(t *testing.T) {
+	r := redactor{keys: []string{"password", "*token*"}, placeholder: DefaultRedactPlaceholder}
+
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{key: "password", want: true},
+		{key: "Password", want: true},
+		{key: "refresh_token_hash", want: true},
+		{key: "user_id", want: false},
+		{key: "password_hint", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := r.matchKey(tt.key); got != tt.want {
+				t.Errorf("redactor.matchKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
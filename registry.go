@@ -0,0 +1,111 @@
+package errwrap
+
+import (
+	"fmt"
+	"sync"
+)
+
+// DuplicateHandler handles error returned when registering a duplicate error
+// definition
+type DuplicateHandler func(err error)
+
+// DuplicateDefinitionError is returned when an error definition is registered
+// with code or code string that has been registered by another error
+// definition
+type DuplicateDefinitionError struct {
+	Existing   *ErrorDefinition // already registered error definition
+	Definition *ErrorDefinition // error definition failed to be registered
+}
+
+func (e *DuplicateDefinitionError) Error() string {
+	if e.Existing.code == e.Definition.code {
+		return fmt.Sprintf("errwrap: duplicate error code %d, used by %s and %s",
+			e.Definition.code, e.Existing.codeString, e.Definition.codeString)
+	}
+	return fmt.Sprintf("errwrap: duplicate error code string %s, used by code %d and %d",
+		e.Definition.codeString, e.Existing.code, e.Definition.code)
+}
+
+// Registry records error definitions, and makes sure there is no error
+// definitions sharing the same code or code string. It is safe to be used
+// concurrently.
+type Registry struct {
+	mu           sync.RWMutex
+	definitions  []*ErrorDefinition
+	byCode       map[int]*ErrorDefinition
+	byCodeString map[string]*ErrorDefinition
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		byCode:       make(map[int]*ErrorDefinition),
+		byCodeString: make(map[string]*ErrorDefinition),
+	}
+}
+
+// Register registers the error definition. Returns *DuplicateDefinitionError if
+// the code or the code string has been registered, in which case the error
+// definition is not registered.
+func (r *Registry) Register(ed *ErrorDefinition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.byCode[ed.code]; ok {
+		return &DuplicateDefinitionError{Existing: existing, Definition: ed}
+	}
+	if existing, ok := r.byCodeString[ed.codeString]; ok {
+		return &DuplicateDefinitionError{Existing: existing, Definition: ed}
+	}
+
+	r.definitions = append(r.definitions, ed)
+	r.byCode[ed.code] = ed
+	r.byCodeString[ed.codeString] = ed
+	return nil
+}
+
+// ByCode returns the error definition registered with given code
+func (r *Registry) ByCode(code int) (*ErrorDefinition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ed, ok := r.byCode[code]
+	return ed, ok
+}
+
+// ByCodeString returns the error definition registered with given code string
+func (r *Registry) ByCodeString(codeString string) (*ErrorDefinition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ed, ok := r.byCodeString[codeString]
+	return ed, ok
+}
+
+// Definitions returns all registered error definitions, in registration order
+func (r *Registry) Definitions() []*ErrorDefinition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	definitions := make([]*ErrorDefinition, len(r.definitions))
+	copy(definitions, r.definitions)
+	return definitions
+}
+
+// DefinitionByCode returns the error definition registered in DefaultFactory
+// registry with given code
+func DefinitionByCode(code int) (*ErrorDefinition, bool) {
+	return DefaultFactory.Registry().ByCode(code)
+}
+
+// DefinitionByCodeString returns the error definition registered in
+// DefaultFactory registry with given code string
+func DefinitionByCodeString(codeString string) (*ErrorDefinition, bool) {
+	return DefaultFactory.Registry().ByCodeString(codeString)
+}
+
+// Definitions returns all error definitions registered in DefaultFactory
+// registry
+func Definitions() []*ErrorDefinition {
+	return DefaultFactory.Registry().Definitions()
+}
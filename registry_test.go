@@ -0,0 +1,181 @@
+package errwrap
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// tests create error definitions with the same code repeatedly, so
+	// duplicates shouldn't panic here
+	config := DefaultFactory.Config()
+	config.DuplicateHandler = func(err error) {}
+	DefaultFactory.SetConfig(config)
+
+	os.Exit(m.Run())
+}
+
+func TestRegistry_Register(t *testing.T) {
+	edTest := &ErrorDefinition{code: 100, codeString: "ErrTest"}
+
+	type args struct {
+		ed *ErrorDefinition
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "success",
+			args: args{
+				ed: &ErrorDefinition{code: 101, codeString: "ErrTestNew"},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "error duplicate code",
+			args: args{
+				ed: &ErrorDefinition{code: 100, codeString: "ErrTestNew"},
+			},
+			wantErr: &DuplicateDefinitionError{
+				Existing:   edTest,
+				Definition: &ErrorDefinition{code: 100, codeString: "ErrTestNew"},
+			},
+		},
+		{
+			name: "error duplicate code string",
+			args: args{
+				ed: &ErrorDefinition{code: 101, codeString: "ErrTest"},
+			},
+			wantErr: &DuplicateDefinitionError{
+				Existing:   edTest,
+				Definition: &ErrorDefinition{code: 101, codeString: "ErrTest"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			if err := r.Register(edTest); err != nil {
+				t.Fatalf("Registry.Register() error = %v", err)
+			}
+
+			err := r.Register(tt.args.ed)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("Registry.Register() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDuplicateDefinitionError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *DuplicateDefinitionError
+		want string
+	}{
+		{
+			name: "success duplicate code",
+			err: &DuplicateDefinitionError{
+				Existing:   &ErrorDefinition{code: 100, codeString: "ErrTest"},
+				Definition: &ErrorDefinition{code: 100, codeString: "ErrTestNew"},
+			},
+			want: "errwrap: duplicate error code 100, used by ErrTest and ErrTestNew",
+		},
+		{
+			name: "success duplicate code string",
+			err: &DuplicateDefinitionError{
+				Existing:   &ErrorDefinition{code: 100, codeString: "ErrTest"},
+				Definition: &ErrorDefinition{code: 101, codeString: "ErrTest"},
+			},
+			want: "errwrap: duplicate error code string ErrTest, used by code 100 and 101",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("DuplicateDefinitionError.Error() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistry_Lookup(t *testing.T) {
+	edTest := &ErrorDefinition{code: 100, codeString: "ErrTest"}
+	edTestNew := &ErrorDefinition{code: 101, codeString: "ErrTestNew"}
+
+	r := NewRegistry()
+	_ = r.Register(edTest)
+	_ = r.Register(edTestNew)
+
+	tests := []struct {
+		name       string
+		code       int
+		codeString string
+		want       *ErrorDefinition
+		wantOk     bool
+	}{
+		{
+			name:       "success",
+			code:       101,
+			codeString: "ErrTestNew",
+			want:       edTestNew,
+			wantOk:     true,
+		},
+		{
+			name:       "success not found",
+			code:       102,
+			codeString: "ErrTestNotFound",
+			want:       nil,
+			wantOk:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotOk := r.ByCode(tt.code)
+			if got != tt.want || gotOk != tt.wantOk {
+				t.Errorf("Registry.ByCode() = %v, %v, want %v, %v", got, gotOk, tt.want, tt.wantOk)
+			}
+
+			got, gotOk = r.ByCodeString(tt.codeString)
+			if got != tt.want || gotOk != tt.wantOk {
+				t.Errorf("Registry.ByCodeString() = %v, %v, want %v, %v", got, gotOk, tt.want, tt.wantOk)
+			}
+		})
+	}
+
+	if got, want := r.Definitions(), []*ErrorDefinition{edTest, edTestNew}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Registry.Definitions() = %v, want %v", got, want)
+	}
+}
+
+func TestNewError_duplicate(t *testing.T) {
+	var gotErr error
+	setDefaultConfig(t, func(config *Config) {
+		config.Registry = NewRegistry()
+		config.DuplicateHandler = func(err error) {
+			gotErr = err
+		}
+	})
+
+	edTest := NewError(100, "ErrTest", ErrorCategory(1))
+	if gotErr != nil {
+		t.Fatalf("NewError() duplicate error = %v, want nil", gotErr)
+	}
+	if got, ok := DefinitionByCode(100); !ok || got != edTest {
+		t.Errorf("DefinitionByCode() = %v, want %v", got, edTest)
+	}
+	if got, ok := DefinitionByCodeString("ErrTest"); !ok || got != edTest {
+		t.Errorf("DefinitionByCodeString() = %v, want %v", got, edTest)
+	}
+
+	NewError(100, "ErrTestNew", ErrorCategory(1))
+	if _, ok := gotErr.(*DuplicateDefinitionError); !ok {
+		t.Errorf("NewError() duplicate error = %v, want *DuplicateDefinitionError", gotErr)
+	}
+	if got := Definitions(); len(got) != 1 {
+		t.Errorf("Definitions() = %v, want 1 definition", got)
+	}
+}
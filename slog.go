@@ -0,0 +1,177 @@
+package errwrap
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"strconv"
+)
+
+// LogValue implements slog.LogValuer, logging the error wrapper as a group of
+// actual error message, code, code string, category, params, error data, stack
+// trace, and the cause error
+func (e *errorWrapper) LogValue() slog.Value {
+	return e.logValue(true)
+}
+
+// errorLogValuer is implemented by errors which can be expanded by SlogHandler
+type errorLogValuer interface {
+	logValue(withStackTrace bool) slog.Value
+}
+
+func (e *errorWrapper) logValue(withStackTrace bool) slog.Value {
+	return errorWrapperLogValue(e, withStackTrace)
+}
+
+// errorWrapperLogValue builds the log group of the error wrapper
+func errorWrapperLogValue(erw ErrorWrapper, withStackTrace bool) slog.Value {
+	attrs := []slog.Attr{
+		slog.String("message", erw.ActualError()),
+		slog.Int("code", erw.Code()),
+		slog.String("code_string", erw.CodeString()),
+		slog.Int("category", int(erw.Category())),
+	}
+	if erw.Masked() {
+		attrs = append(attrs, slog.String("masked_message", erw.Error()))
+	}
+
+	if params := erw.Params(); len(params) > 0 {
+		attrs = append(attrs, mapAttr("params", params))
+	}
+	if data := erw.Data(); len(data) > 0 {
+		attrs = append(attrs, mapAttr("data", data))
+	}
+
+	if fields := erw.Fields(); len(fields) > 0 {
+		fieldAttrs := make([]slog.Attr, len(fields))
+		for i, field := range fields {
+			fieldAttrs[i] = slog.Group(strconv.Itoa(i), "field", field.Field, "code", field.Code, "message", field.Message)
+		}
+		attrs = append(attrs, slog.Attr{Key: "fields", Value: slog.GroupValue(fieldAttrs...)})
+	}
+
+	if withStackTrace {
+		if stackTrace := erw.StackTrace(); len(stackTrace) > 0 {
+			attrs = append(attrs, slog.Any("stack_trace", stackTrace))
+		}
+	}
+
+	if cause := erw.Unwrap(); cause != nil {
+		switch cause := cause.(type) {
+		case errorLogValuer:
+			attrs = append(attrs, slog.Attr{Key: "cause", Value: cause.logValue(withStackTrace)})
+		case ErrorWrapper:
+			attrs = append(attrs, slog.Attr{Key: "cause", Value: errorWrapperLogValue(cause, withStackTrace)})
+		default:
+			attrs = append(attrs, slog.String("cause", cause.Error()))
+		}
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// mapAttr builds log group of the map, sorted by key
+func mapAttr(key string, m map[string]interface{}) slog.Attr {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, slog.Any(k, m[k]))
+	}
+	return slog.Attr{Key: key, Value: slog.GroupValue(attrs...)}
+}
+
+// SlogHandlerOptions are options for SlogHandler
+type SlogHandlerOptions struct {
+	// StackTraceLevel is the minimum record level to log stack traces of the
+	// error wrappers. Stack traces are always logged if this is nil. Errors in
+	// attributes added using WithAttrs are logged without stack trace if this
+	// is set, as the record level is not known yet.
+	StackTraceLevel slog.Leveler
+}
+
+// SlogHandler is slog.Handler expanding error wrappers in the record
+// attributes, including error wrappers wrapped by other errors, before passing
+// the record to the next handler
+type SlogHandler struct {
+	next slog.Handler
+	opts SlogHandlerOptions
+}
+
+// NewSlogHandler creates SlogHandler passing the records to next handler.
+// Default options are used if opts is nil.
+func NewSlogHandler(next slog.Handler, opts *SlogHandlerOptions) *SlogHandler {
+	h := &SlogHandler{next: next}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+// Enabled reports whether the next handler handles records at given level
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle expands error wrappers in the record attributes and passes the
+// record to the next handler
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	withStackTrace := h.opts.StackTraceLevel == nil || r.Level >= h.opts.StackTraceLevel.Level()
+
+	expanded := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(attr slog.Attr) bool {
+		expanded.AddAttrs(expandAttr(attr, withStackTrace))
+		return true
+	})
+	return h.next.Handle(ctx, expanded)
+}
+
+// WithAttrs returns handler with given attributes, in which error wrappers
+// are expanded
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	withStackTrace := h.opts.StackTraceLevel == nil
+
+	expanded := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		expanded[i] = expandAttr(attr, withStackTrace)
+	}
+	return &SlogHandler{next: h.next.WithAttrs(expanded), opts: h.opts}
+}
+
+// WithGroup returns handler with given group
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	return &SlogHandler{next: h.next.WithGroup(name), opts: h.opts}
+}
+
+// expandAttr replaces error values containing error wrapper with the error
+// wrapper log group, recursing into groups
+func expandAttr(attr slog.Attr, withStackTrace bool) slog.Attr {
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		expanded := make([]slog.Attr, len(group))
+		for i, a := range group {
+			expanded[i] = expandAttr(a, withStackTrace)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(expanded...)}
+
+	case slog.KindAny, slog.KindLogValuer:
+		err, ok := attr.Value.Any().(error)
+		if !ok {
+			return attr
+		}
+		var lv errorLogValuer
+		if errors.As(err, &lv) {
+			return slog.Attr{Key: attr.Key, Value: lv.logValue(withStackTrace)}
+		}
+		if erw, ok := As(err); ok {
+			return slog.Attr{Key: attr.Key, Value: errorWrapperLogValue(erw, withStackTrace)}
+		}
+	}
+	return attr
+}
@@ -0,0 +1,100 @@
+package errwrap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestErrorWrapper_LogValue(t *testing.T) {
+	ctx := InjectErrorData(context.Background(), ErrorData{"user_id": 1})
+	erw := NewError(100, "ErrTest", ErrorCategory(1)).MaskedMessage("Masked").
+		New(ctx, "Test error message: %s", "foo")
+
+	got := erw.(slog.LogValuer).LogValue()
+	if got.Kind() != slog.KindGroup {
+		t.Fatalf("LogValue() kind = %v, want %v", got.Kind(), slog.KindGroup)
+	}
+
+	attrs := make(map[string]slog.Value)
+	for _, attr := range got.Group() {
+		attrs[attr.Key] = attr.Value
+	}
+
+	want := map[string]string{
+		"message":        "Test error message: foo (100)",
+		"code":           "100",
+		"code_string":    "ErrTest",
+		"category":       "1",
+		"masked_message": "Masked (100)",
+		"data":           "[user_id=1]",
+	}
+	for k, v := range want {
+		if got := attrs[k].String(); got != v {
+			t.Errorf("LogValue() %s = %v, want %v", k, got, v)
+		}
+	}
+	if stackTrace, _ := attrs["stack_trace"].Any().([]string); len(stackTrace) == 0 {
+		t.Errorf("LogValue() stack_trace is empty")
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	ed := NewError(100, "ErrTest", ErrorCategory(1))
+
+	tests := []struct {
+		name           string
+		opts           *SlogHandlerOptions
+		level          slog.Level
+		err            error
+		wantStackTrace bool
+	}{
+		{
+			name:           "success",
+			level:          slog.LevelError,
+			err:            ed.NewWithoutContext("Test error message"),
+			wantStackTrace: true,
+		},
+		{
+			name:           "success wrapped",
+			level:          slog.LevelError,
+			err:            fmt.Errorf("handler: %w", ed.NewWithoutContext("Test error message")),
+			wantStackTrace: true,
+		},
+		{
+			name:           "success stack trace omitted below level",
+			opts:           &SlogHandlerOptions{StackTraceLevel: slog.LevelError},
+			level:          slog.LevelWarn,
+			err:            ed.NewWithoutContext("Test error message"),
+			wantStackTrace: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(NewSlogHandler(slog.NewJSONHandler(&buf, nil), tt.opts))
+			logger.WithGroup("request").Log(context.Background(), tt.level, "failed", "err", tt.err)
+
+			var got struct {
+				Request struct {
+					Err map[string]interface{} `json:"err"`
+				} `json:"request"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v, log = %s", err, buf.String())
+			}
+
+			errAttrs := got.Request.Err
+			if !reflect.DeepEqual(errAttrs["code_string"], "ErrTest") || !reflect.DeepEqual(errAttrs["message"], "Test error message (100)") {
+				t.Errorf("SlogHandler.Handle() err = %v", errAttrs)
+			}
+			if _, ok := errAttrs["stack_trace"]; ok != tt.wantStackTrace {
+				t.Errorf("SlogHandler.Handle() has stack_trace = %v, want %v", ok, tt.wantStackTrace)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package errwrap
+
+import (
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxStackDepth defines the maximum number of frames captured in stack trace
+const maxStackDepth = 64
+
+// Frame is a single frame of the stack trace
+type Frame struct {
+	File     string `json:"file"`     // path of the source file
+	Line     int    `json:"line"`     // line number in the source file
+	Function string `json:"function"` // function name without package, e.g. (*T).Method
+	Package  string `json:"package"`  // package path, e.g. github.com/rapidashorg/errwrap
+}
+
+// newFrame creates frame from runtime frame, splitting the fully qualified
+// function name into package path and function name
+func newFrame(frame runtime.Frame) Frame {
+	pkg, fn := "", frame.Function
+
+	// the first dot after the last slash separates package and function name,
+	// as dots in the last element of package path are escaped as %2e
+	lastSlash := strings.LastIndex(fn, "/")
+	if dot := strings.Index(fn[lastSlash+1:], "."); dot >= 0 {
+		pkg, fn = fn[:lastSlash+1+dot], fn[lastSlash+1+dot+1:]
+		pkg = strings.ReplaceAll(pkg, "%2e", ".")
+	}
+
+	return Frame{
+		File:     frame.File,
+		Line:     frame.Line,
+		Function: fn,
+		Package:  pkg,
+	}
+}
+
+// FullFunction returns the fully qualified function name, e.g.
+// github.com/rapidashorg/errwrap.(*T).Method
+func (f Frame) FullFunction() string {
+	if f.Package == "" {
+		return f.Function
+	}
+	return f.Package + "." + f.Function
+}
+
+// format formats the frame as stack trace line using given mode. The line is
+// built using a single allocation, as it is called for every frame when the
+// stack trace is read.
+func (f Frame) format(mode StackTraceMode) string {
+	if mode == StackTraceModeFuncOnly {
+		return f.FullFunction()
+	}
+
+	var lineBuf [20]byte
+	line := strconv.AppendInt(lineBuf[:0], int64(f.Line), 10)
+
+	var b strings.Builder
+	if mode == StackTraceModeLineOnly {
+		b.Grow(len(f.File) + 1 + len(line))
+	} else {
+		b.Grow(len(f.File) + 1 + len(line) + 2 + len(f.Package) + 1 + len(f.Function) + 1)
+	}
+
+	b.WriteString(f.File)
+	b.WriteByte(':')
+	b.Write(line)
+	if mode != StackTraceModeLineOnly {
+		b.WriteString(" (")
+		if f.Package != "" {
+			b.WriteString(f.Package)
+			b.WriteByte('.')
+		}
+		b.WriteString(f.Function)
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
+// errwrapPackage is the package path of this package, used to drop errwrap
+// internal frames from the stack trace
+var errwrapPackage = reflect.TypeOf(ErrorDefinition{}).PkgPath()
+
+// StackTraceFilter filters and shortens stack trace frames. Unlike
+// Config.PackagePrefix, which stops the stack trace at the first frame not
+// matching the prefix, frames not matching the filter are skipped, so frames
+// called through other libraries, e.g. middlewares or net/http, are kept.
+// Frames of runtime and errwrap internals are always dropped.
+type StackTraceFilter struct {
+	// IncludePrefixes keeps only frames whose fully qualified function name
+	// starts with one of the prefixes, e.g. module paths of a monorepo. All
+	// frames are kept if empty.
+	IncludePrefixes []string
+
+	// ExcludePrefixes skips frames whose fully qualified function name starts
+	// with one of the prefixes, e.g. "net/http."
+	ExcludePrefixes []string
+
+	// TrimPathPrefixes trims the first matching prefix from the file path of
+	// the frames, e.g. module root directory, to shorten the file path
+	TrimPathPrefixes []string
+}
+
+// keep checks whether the frame is kept in the stack trace
+func (f StackTraceFilter) keep(frame Frame) bool {
+	if isInternalFrame(frame) {
+		return false
+	}
+
+	fn := frame.FullFunction()
+	if len(f.IncludePrefixes) > 0 && !hasAnyPrefix(fn, f.IncludePrefixes) {
+		return false
+	}
+	return !hasAnyPrefix(fn, f.ExcludePrefixes)
+}
+
+// trimPath trims the first matching path prefix from the file path
+func (f StackTraceFilter) trimPath(file string) string {
+	for _, prefix := range f.TrimPathPrefixes {
+		if prefix != "" && strings.HasPrefix(file, prefix) {
+			return strings.TrimPrefix(file[len(prefix):], "/")
+		}
+	}
+	return file
+}
+
+// isInternalFrame checks whether the frame is part of runtime or errwrap
+// internals
+func isInternalFrame(frame Frame) bool {
+	return frame.Package == "runtime" || strings.HasPrefix(frame.Package, "runtime/") ||
+		frame.Package == errwrapPackage || strings.HasPrefix(frame.Package, errwrapPackage+"/")
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// stack contains program counters captured when the error wrapper is created.
+// The program counters are only resolved into frames when the stack trace is
+// read, as most of errors never have their stack trace read.
+type stack struct {
+	pcs    []uintptr
+	depth  int // maximum number of frames kept after filtering
+	mode   StackTraceMode
+	prefix string
+	filter StackTraceFilter
+
+	once   sync.Once
+	frames []Frame
+}
+
+// newResolvedStack creates stack from already resolved frames, e.g. frames
+// received from another service
+func newResolvedStack(frames []Frame, mode StackTraceMode) *stack {
+	s := &stack{mode: mode}
+	s.once.Do(func() {
+		s.frames = frames
+	})
+	return s
+}
+
+// fillStackTrace captures at most depth frames of errorWrapper stack, skipping
+// offset number of frames above the caller of fillStackTrace. The depth is
+// applied after internal and filtered frames are dropped, so up to
+// maxStackDepth program counters are captured.
+func (e *errorWrapper) fillStackTrace(offset int, depth int) {
+	if depth <= 0 {
+		return
+	}
+	if depth > maxStackDepth {
+		depth = maxStackDepth
+	}
+
+	var pcs [maxStackDepth]uintptr
+
+	// skip runtime.Callers, fillStackTrace, and the offset frames
+	n := runtime.Callers(2+offset, pcs[:])
+
+	config := e.getFactory().getConfig()
+	e.stack = &stack{
+		pcs:    append([]uintptr(nil), pcs[:n]...),
+		depth:  depth,
+		mode:   config.StackTraceMode,
+		prefix: config.PackagePrefix,
+		filter: config.StackTraceFilter,
+	}
+}
+
+// resolve resolves program counters into frames, trimmed using the package
+// prefix and filtered using the stack trace filter at the time the stack is
+// captured, keeping at most depth frames
+func (s *stack) resolve() []Frame {
+	s.once.Do(func() {
+		frames := make([]Frame, 0, len(s.pcs))
+
+		callersFrames := runtime.CallersFrames(s.pcs)
+		for len(frames) < s.depth {
+			frame, more := callersFrames.Next()
+			if frame.PC == 0 {
+				break
+			}
+
+			if s.prefix != "" && !strings.HasPrefix(frame.Function, s.prefix) {
+				break
+			}
+
+			f := newFrame(frame)
+			if s.filter.keep(f) {
+				f.File = s.filter.trimPath(f.File)
+				frames = append(frames, f)
+			}
+
+			if !more {
+				break
+			}
+		}
+
+		s.frames = frames
+	})
+	return s.frames
+}
+
+// lines formats the frames into stack trace lines, using the stack trace mode
+// at the time the stack is captured
+func (s *stack) lines() []string {
+	frames := s.resolve()
+
+	lines := make([]string, 0, len(frames))
+	for _, frame := range frames {
+		lines = append(lines, frame.format(s.mode))
+	}
+	return lines
+}
@@ -0,0 +1,344 @@
+package errwrap_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/rapidashorg/errwrap"
+)
+
+// The stack trace tests are in the external test package, as frames of the
+// errwrap package, including its tests, are dropped from the stack trace.
+
+// newNested creates error wrapper n calls below the caller, so the stack trace
+// has more frames than the test function and testing.tRunner
+func newNested(n int, ed *errwrap.ErrorDefinition) errwrap.ErrorWrapper {
+	if n == 0 {
+		return ed.NewWithoutContext("Test error message")
+	}
+	return newNested(n-1, ed)
+}
+
+func TestErrorWrapper_StackTrace(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       errwrap.StackTraceMode
+		prefix     string
+		wantFirst  string
+		wantLength int
+	}{
+		{
+			name:      "success full",
+			mode:      errwrap.StackTraceModeFull,
+			wantFirst: "stack_external_test.go:%d (github.com/rapidashorg/errwrap_test.TestErrorWrapper_StackTrace.func1)",
+		},
+		{
+			name:      "success line only",
+			mode:      errwrap.StackTraceModeLineOnly,
+			wantFirst: "stack_external_test.go:%d",
+		},
+		{
+			name:      "success func only",
+			mode:      errwrap.StackTraceModeFuncOnly,
+			wantFirst: "github.com/rapidashorg/errwrap_test.TestErrorWrapper_StackTrace.func1",
+		},
+		{
+			name:       "success trimmed by package prefix",
+			mode:       errwrap.StackTraceModeFuncOnly,
+			prefix:     "github.com/rapidashorg/errwrap",
+			wantFirst:  "github.com/rapidashorg/errwrap_test.TestErrorWrapper_StackTrace.func1",
+			wantLength: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := errwrap.DefaultConfig()
+			config.StackTraceMode = tt.mode
+			config.PackagePrefix = tt.prefix
+			f := errwrap.NewFactory(config)
+			ed := f.NewError(100, "ErrTest", errwrap.ErrorCategory(1))
+
+			_, _, line, _ := runtime.Caller(0)
+			erw := ed.NewWithoutContext("Test error message")
+
+			// changing the mode after the error is created doesn't change
+			// the stack trace
+			config.StackTraceMode = errwrap.StackTraceModeFull
+			f.SetConfig(config)
+
+			got := erw.StackTrace()
+			if len(got) == 0 {
+				t.Fatalf("ErrorWrapper.StackTrace() is empty")
+			}
+
+			wantFirst := tt.wantFirst
+			if strings.Contains(wantFirst, "%d") {
+				wantFirst = fmt.Sprintf(wantFirst, line+1)
+			}
+			if !strings.HasSuffix(got[0], wantFirst) {
+				t.Errorf("ErrorWrapper.StackTrace()[0] = %v, want suffix %v", got[0], wantFirst)
+			}
+			if tt.wantLength > 0 && len(got) != tt.wantLength {
+				t.Errorf("len(ErrorWrapper.StackTrace()) = %v, want %v", len(got), tt.wantLength)
+			}
+		})
+	}
+}
+
+func TestErrorDefinition_stackTraceCaller(t *testing.T) {
+	f := errwrap.NewFactory(errwrap.DefaultConfig())
+	ctx := context.Background()
+	cause := errors.New("connection refused")
+
+	tests := []struct {
+		name string
+		new  func() errwrap.ErrorWrapper
+	}{
+		{
+			name: "New",
+			new: func() errwrap.ErrorWrapper {
+				return f.NewError(100, "ErrNew", errwrap.ErrorCategory(1)).New(ctx, "Test error message")
+			},
+		},
+		{
+			name: "NewWithoutContext",
+			new: func() errwrap.ErrorWrapper {
+				return f.NewError(101, "ErrNewWithoutContext", errwrap.ErrorCategory(1)).NewWithoutContext("Test error message")
+			},
+		},
+		{
+			name: "Wrap",
+			new: func() errwrap.ErrorWrapper {
+				return f.NewError(102, "ErrWrap", errwrap.ErrorCategory(1)).Wrap(ctx, cause, "Test error message")
+			},
+		},
+		{
+			name: "WrapWithoutContext",
+			new: func() errwrap.ErrorWrapper {
+				return f.NewError(103, "ErrWrapWithoutContext", errwrap.ErrorCategory(1)).WrapWithoutContext(cause, "Test error message")
+			},
+		},
+		{
+			name: "NewArgs",
+			new: func() errwrap.ErrorWrapper {
+				return f.NewError(104, "ErrNewArgs", errwrap.ErrorCategory(1)).MessageFormat("User %d not found").NewArgs(ctx, 1)
+			},
+		},
+		{
+			name: "NewArgsWithoutContext",
+			new: func() errwrap.ErrorWrapper {
+				return f.NewError(105, "ErrNewArgsWithoutContext", errwrap.ErrorCategory(1)).MessageFormat("User %d not found").NewArgsWithoutContext(1)
+			},
+		},
+		{
+			name: "NewParams",
+			new: func() errwrap.ErrorWrapper {
+				return f.NewError(106, "ErrNewParams", errwrap.ErrorCategory(1)).MessageTemplate("User {user_id} not found").NewParams(ctx, errwrap.Params{"user_id": 1})
+			},
+		},
+		{
+			name: "NewParamsWithoutContext",
+			new: func() errwrap.ErrorWrapper {
+				return f.NewError(107, "ErrNewParamsWithoutContext", errwrap.ErrorCategory(1)).MessageTemplate("User {user_id} not found").NewParamsWithoutContext(errwrap.Params{"user_id": 1})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frames := tt.new().Frames()
+			if len(frames) == 0 || !strings.HasPrefix(frames[0].Function, "TestErrorDefinition_stackTraceCaller.") {
+				t.Errorf("ErrorWrapper.Frames() = %v, want the caller first", frames)
+			}
+		})
+	}
+}
+
+func TestErrorDefinition_StackTraceDepth(t *testing.T) {
+	f := errwrap.NewFactory(errwrap.DefaultConfig())
+
+	tests := []struct {
+		name       string
+		ed         *errwrap.ErrorDefinition
+		wantDepth  int
+		wantLength int
+	}{
+		{
+			name:       "success default",
+			ed:         f.NewError(100, "ErrDefault", errwrap.ErrorCategory(1)),
+			wantDepth:  errwrap.DefaultConfig().StackTraceDepth,
+			wantLength: -1,
+		},
+		{
+			name:       "success without stack trace",
+			ed:         f.NewError(101, "ErrWithoutStackTrace", errwrap.ErrorCategory(1)).WithoutStackTrace(),
+			wantDepth:  0,
+			wantLength: 0,
+		},
+		{
+			name:       "success caller only",
+			ed:         f.NewError(102, "ErrCallerOnly", errwrap.ErrorCategory(1)).StackTraceCallerOnly(),
+			wantDepth:  1,
+			wantLength: 1,
+		},
+		{
+			name:       "success limited depth",
+			ed:         f.NewError(103, "ErrLimitedDepth", errwrap.ErrorCategory(1)).StackTraceDepth(2),
+			wantDepth:  2,
+			wantLength: 2,
+		},
+		{
+			name:       "success full",
+			ed:         f.NewError(104, "ErrFull", errwrap.ErrorCategory(1)).StackTraceFull(),
+			wantDepth:  errwrap.MaxStackDepth,
+			wantLength: -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errwrap.StackTraceDepthOf(tt.ed); got != tt.wantDepth {
+				t.Errorf("ErrorDefinition.stackTraceDepth() = %v, want %v", got, tt.wantDepth)
+			}
+
+			got := newNested(2, tt.ed).StackTrace()
+			if tt.wantLength >= 0 && len(got) != tt.wantLength {
+				t.Errorf("len(ErrorWrapper.StackTrace()) = %v, want %v", len(got), tt.wantLength)
+			}
+			if tt.wantLength < 0 && len(got) <= 2 {
+				t.Errorf("len(ErrorWrapper.StackTrace()) = %v, want full stack trace", len(got))
+			}
+		})
+	}
+}
+
+func TestErrorWrapper_Frames(t *testing.T) {
+	ed := errwrap.NewFactory(errwrap.DefaultConfig()).NewError(100, "ErrTest", errwrap.ErrorCategory(1))
+
+	_, file, line, _ := runtime.Caller(0)
+	erw := ed.NewWithoutContext("Test error message")
+
+	got := erw.Frames()
+	if len(got) == 0 {
+		t.Fatalf("ErrorWrapper.Frames() is empty")
+	}
+
+	want := errwrap.Frame{
+		File:     file,
+		Line:     line + 1,
+		Function: "TestErrorWrapper_Frames",
+		Package:  "github.com/rapidashorg/errwrap_test",
+	}
+	if got[0] != want {
+		t.Errorf("ErrorWrapper.Frames()[0] = %v, want %v", got[0], want)
+	}
+
+	if stackTrace := erw.StackTrace(); len(stackTrace) != len(got) {
+		t.Errorf("len(ErrorWrapper.StackTrace()) = %v, want %v", len(stackTrace), len(got))
+	}
+
+	got[0].Function = "modified"
+	if frames := erw.Frames(); frames[0] != want {
+		t.Errorf("ErrorWrapper.Frames()[0] after modifying returned frames = %v, want %v", frames[0], want)
+	}
+	if stackTrace := erw.StackTrace(); strings.Contains(stackTrace[0], "modified") {
+		t.Errorf("ErrorWrapper.StackTrace()[0] after modifying returned frames = %v", stackTrace[0])
+	}
+}
+
+func TestStackTraceFilter(t *testing.T) {
+	_, file, _, _ := runtime.Caller(0)
+	dir := filepath.Dir(file)
+
+	newError := func(filter errwrap.StackTraceFilter) *errwrap.ErrorDefinition {
+		config := errwrap.DefaultConfig()
+		config.StackTraceFilter = filter
+		return errwrap.NewFactory(config).NewError(100, "ErrTest", errwrap.ErrorCategory(1))
+	}
+
+	tests := []struct {
+		name       string
+		filter     errwrap.StackTraceFilter
+		wantFrames []string
+	}{
+		{
+			name:   "success default",
+			filter: errwrap.StackTraceFilter{},
+			wantFrames: []string{
+				"github.com/rapidashorg/errwrap_test.TestStackTraceFilter.func2",
+				"testing.tRunner",
+			},
+		},
+		{
+			name: "success include prefixes",
+			filter: errwrap.StackTraceFilter{
+				IncludePrefixes: []string{"example.com/", "github.com/rapidashorg/"},
+			},
+			wantFrames: []string{
+				"github.com/rapidashorg/errwrap_test.TestStackTraceFilter.func2",
+			},
+		},
+		{
+			name: "success exclude prefixes",
+			filter: errwrap.StackTraceFilter{
+				ExcludePrefixes: []string{"testing."},
+			},
+			wantFrames: []string{
+				"github.com/rapidashorg/errwrap_test.TestStackTraceFilter.func2",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			erw := newError(tt.filter).NewWithoutContext("Test error message")
+
+			got := make([]string, 0)
+			for _, frame := range erw.Frames() {
+				got = append(got, frame.FullFunction())
+			}
+			if !reflect.DeepEqual(got, tt.wantFrames) {
+				t.Errorf("ErrorWrapper.Frames() = %v, want %v", got, tt.wantFrames)
+			}
+		})
+	}
+
+	t.Run("success trim path prefixes", func(t *testing.T) {
+		erw := newError(errwrap.StackTraceFilter{
+			TrimPathPrefixes: []string{"/non/existent", dir},
+		}).NewWithoutContext("Test error message")
+
+		if got := erw.Frames()[0].File; got != "stack_external_test.go" {
+			t.Errorf("ErrorWrapper.Frames()[0].File = %v, want %v", got, "stack_external_test.go")
+		}
+	})
+
+	t.Run("success depth applied after filter", func(t *testing.T) {
+		ed := newError(errwrap.StackTraceFilter{
+			ExcludePrefixes: []string{"sort."},
+		}).StackTraceDepth(2)
+
+		var erw errwrap.ErrorWrapper
+		values := []int{2, 1}
+		sort.Slice(values, func(i, j int) bool {
+			if erw == nil {
+				erw = ed.NewWithoutContext("Test error message")
+			}
+			return values[i] < values[j]
+		})
+
+		frames := erw.Frames()
+		if len(frames) != 2 {
+			t.Fatalf("ErrorWrapper.Frames() = %v, want 2 frames", frames)
+		}
+		for _, frame := range frames {
+			if strings.HasPrefix(frame.FullFunction(), "sort.") {
+				t.Errorf("ErrorWrapper.Frames() contains excluded frame %v", frame.FullFunction())
+			}
+		}
+	})
+}
@@ -0,0 +1,206 @@
+package errwrap
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func Test_newFrame(t *testing.T) {
+	tests := []struct {
+		name             string
+		frame            runtime.Frame
+		want             Frame
+		wantFullFunction string
+	}{
+		{
+			name: "success function",
+			frame: runtime.Frame{
+				File:     "/go/src/github.com/rapidashorg/errwrap/wrapper.go",
+				Line:     10,
+				Function: "github.com/rapidashorg/errwrap.Convert",
+			},
+			want: Frame{
+				File:     "/go/src/github.com/rapidashorg/errwrap/wrapper.go",
+				Line:     10,
+				Function: "Convert",
+				Package:  "github.com/rapidashorg/errwrap",
+			},
+			wantFullFunction: "github.com/rapidashorg/errwrap.Convert",
+		},
+		{
+			name: "success method with dotted package path",
+			frame: runtime.Frame{
+				File:     "/go/pkg/mod/gopkg.in/yaml.v3/decode.go",
+				Line:     20,
+				Function: "gopkg.in/yaml%2ev3.(*decoder).unmarshal.func1",
+			},
+			want: Frame{
+				File:     "/go/pkg/mod/gopkg.in/yaml.v3/decode.go",
+				Line:     20,
+				Function: "(*decoder).unmarshal.func1",
+				Package:  "gopkg.in/yaml.v3",
+			},
+			wantFullFunction: "gopkg.in/yaml.v3.(*decoder).unmarshal.func1",
+		},
+		{
+			name: "success main package",
+			frame: runtime.Frame{
+				File:     "/app/main.go",
+				Line:     30,
+				Function: "main.main",
+			},
+			want: Frame{
+				File:     "/app/main.go",
+				Line:     30,
+				Function: "main",
+				Package:  "main",
+			},
+			wantFullFunction: "main.main",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newFrame(tt.frame)
+			if got != tt.want {
+				t.Errorf("newFrame() = %v, want %v", got, tt.want)
+			}
+			if got.FullFunction() != tt.wantFullFunction {
+				t.Errorf("Frame.FullFunction() = %v, want %v", got.FullFunction(), tt.wantFullFunction)
+			}
+		})
+	}
+}
+
+func Test_errorWrapper_Frames(t *testing.T) {
+	if frames := (&errorWrapper{}).Frames(); frames != nil {
+		t.Errorf("errorWrapper.Frames() = %v, want nil", frames)
+	}
+}
+
+func Test_isInternalFrame(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame Frame
+		want  bool
+	}{
+		{
+			name:  "success runtime",
+			frame: Frame{File: "/usr/local/go/src/runtime/asm_amd64.s", Function: "goexit", Package: "runtime"},
+			want:  true,
+		},
+		{
+			name:  "success errwrap",
+			frame: Frame{File: "/go/src/github.com/rapidashorg/errwrap/wrapper.go", Function: "Convert", Package: "github.com/rapidashorg/errwrap"},
+			want:  true,
+		},
+		{
+			name:  "success errwrap subpackage",
+			frame: Frame{File: "/go/src/github.com/rapidashorg/errwrap/sub/sub.go", Function: "Func", Package: "github.com/rapidashorg/errwrap/sub"},
+			want:  true,
+		},
+		{
+			name:  "success errwrap test",
+			frame: Frame{File: "/go/src/github.com/rapidashorg/errwrap/wrapper_test.go", Function: "TestConvert", Package: "github.com/rapidashorg/errwrap"},
+			want:  true,
+		},
+		{
+			name:  "success errwrap external test",
+			frame: Frame{File: "/go/src/github.com/rapidashorg/errwrap/stack_external_test.go", Function: "TestStackTraceFilter", Package: "github.com/rapidashorg/errwrap_test"},
+			want:  false,
+		},
+		{
+			name:  "success other package",
+			frame: Frame{File: "/go/src/github.com/rapidashorg/errwrapper/main.go", Function: "main", Package: "github.com/rapidashorg/errwrapper"},
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isInternalFrame(tt.frame); got != tt.want {
+				t.Errorf("isInternalFrame() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_errorWrapper_StackTrace_concurrent(t *testing.T) {
+	erw := NewError(100, "ErrTest", ErrorCategory(1)).NewWithoutContext("Test error message")
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if len(erw.StackTrace()) == 0 {
+				t.Error("errorWrapper.StackTrace() is empty")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkErrorDefinition_New(b *testing.B) {
+	oldFillStackTrace := func(e *errorWrapper, offset int) {
+		lines := make([]string, 0)
+		prefix := DefaultFactory.Config().PackagePrefix
+
+		for i := 1 + offset; ; i++ {
+			fnptr, file, line, ok := runtime.Caller(i)
+			if !ok {
+				break
+			}
+
+			funcName := runtime.FuncForPC(fnptr).Name()
+			if prefix != "" && !strings.HasPrefix(funcName, prefix) {
+				break
+			}
+
+			lines = append(lines, fmt.Sprintf("%s:%d (%s)", file, line, funcName))
+		}
+
+		e.stackTrace = lines
+	}
+
+	oldNew := func(ed *ErrorDefinition, rawMessage string, args ...interface{}) ErrorWrapper {
+		erw := newErrorWrapper(nil, ed, rawMessage, args...)
+		oldFillStackTrace(erw, 1)
+		return erw
+	}
+	newNew := func(ed *ErrorDefinition, rawMessage string, args ...interface{}) ErrorWrapper {
+		return ed.NewWithoutContext(rawMessage, args...)
+	}
+
+	ed := NewError(100, "ErrTest", ErrorCategory(1))
+
+	scenario := func(b *testing.B, newMethod func(ed *ErrorDefinition, rawMessage string, args ...interface{}) ErrorWrapper, withRead bool) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			erw := newMethod(ed, "Test error message: %s", "Foo")
+			if withRead && len(erw.StackTrace()) == 0 {
+				b.Error("stack trace is empty")
+			}
+		}
+	}
+
+	b.Run("new method", func(b *testing.B) {
+		scenario(b, newNew, false)
+	})
+	b.Run("old method", func(b *testing.B) {
+		scenario(b, oldNew, false)
+	})
+	b.Run("new method with read", func(b *testing.B) {
+		scenario(b, newNew, true)
+	})
+	b.Run("old method with read", func(b *testing.B) {
+		scenario(b, oldNew, true)
+	})
+
+	//Results
+	//BenchmarkErrorDefinition_New/new_method                           200000               676 ns/op             528 B/op          6 allocs/op
+	//BenchmarkErrorDefinition_New/old_method                           200000              6458 ns/op            2368 B/op         36 allocs/op
+	//BenchmarkErrorDefinition_New/new_method_with_read                 200000              3622 ns/op            1904 B/op         17 allocs/op
+	//BenchmarkErrorDefinition_New/old_method_with_read                 200000              5897 ns/op            2368 B/op         36 allocs/op
+}
@@ -0,0 +1,197 @@
+package errwrap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Params contains the named parameters of message template
+type Params map[string]interface{}
+
+// messageTemplate is a parsed message template with named placeholders, e.g.
+// "User {user_id} not found". "{{" and "}}" are escaped braces.
+type messageTemplate struct {
+	raw   string
+	parts []templatePart
+}
+
+// templatePart is either literal text or a placeholder
+type templatePart struct {
+	text        string
+	placeholder bool
+}
+
+// parseTemplate parses the message template. Braces which don't form a valid
+// placeholder are kept as literal text.
+func parseTemplate(raw string) *messageTemplate {
+	t := &messageTemplate{raw: raw}
+
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			t.parts = append(t.parts, templatePart{text: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		if (c == '{' || c == '}') && i+1 < len(raw) && raw[i+1] == c {
+			literal.WriteByte(c)
+			i++
+			continue
+		}
+
+		if c == '{' {
+			if end := strings.IndexByte(raw[i+1:], '}'); end > 0 && isPlaceholderName(raw[i+1:i+1+end]) {
+				flush()
+				t.parts = append(t.parts, templatePart{text: raw[i+1 : i+1+end], placeholder: true})
+				i += end + 1
+				continue
+			}
+		}
+
+		literal.WriteByte(c)
+	}
+	flush()
+
+	return t
+}
+
+// isPlaceholderName reports whether the name only contains letters, digits,
+// underscores, and dots
+func isPlaceholderName(name string) bool {
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// placeholders returns the placeholder names, in order of appearance
+func (t *messageTemplate) placeholders() []string {
+	var names []string
+	for _, part := range t.parts {
+		if part.placeholder {
+			names = append(names, part.text)
+		}
+	}
+	return names
+}
+
+// render fills the placeholders with the params. Missing params are rendered
+// as "%!{name}(MISSING)", following fmt.
+func (t *messageTemplate) render(params Params) string {
+	var b strings.Builder
+	for _, part := range t.parts {
+		if !part.placeholder {
+			b.WriteString(part.text)
+			continue
+		}
+
+		v, ok := params[part.text]
+		if !ok {
+			fmt.Fprintf(&b, "%%!{%s}(MISSING)", part.text)
+			continue
+		}
+		fmt.Fprint(&b, v)
+	}
+	return b.String()
+}
+
+// MessageTemplate sets the message template with named placeholders, e.g.
+// "User {user_id} not found", used by NewParams. Use "{{" and "}}" to write
+// literal braces.
+func (ed *ErrorDefinition) MessageTemplate(template string) *ErrorDefinition {
+	ed.template = parseTemplate(template)
+	return ed
+}
+
+// MissingMessageError is the panic value when an error definition is used to
+// create error wrappers from the message it owns, but the message is not set,
+// e.g. NewParams called without MessageTemplate, or NewArgs called without
+// MessageFormat
+type MissingMessageError struct {
+	Definition *ErrorDefinition
+	Method     string // method called, e.g. "NewParams"
+	Setter     string // method setting the message, e.g. "MessageTemplate"
+}
+
+func (e *MissingMessageError) Error() string {
+	return fmt.Sprintf("errwrap: %s called on %s without %s", e.Method, e.Definition.codeString, e.Setter)
+}
+
+// NewParamsWithoutContext creates new ErrorWrapper based on error definition
+// with the message template filled from params, without passed context. It
+// panics with *MissingMessageError if the message template is not set.
+func (ed *ErrorDefinition) NewParamsWithoutContext(params Params) ErrorWrapper {
+	erw := newTemplateErrorWrapper(context.Background(), ed, "NewParamsWithoutContext", params)
+	erw.fillStackTrace(1, ed.stackTraceDepth())
+	return erw
+}
+
+// NewParams creates new ErrorWrapper based on error definition with the
+// message template filled from params. The params are exposed as structured
+// fields using ErrorWrapper.Params. It panics with *MissingMessageError if the
+// message template is not set.
+func (ed *ErrorDefinition) NewParams(ctx context.Context, params Params) ErrorWrapper {
+	erw := newTemplateErrorWrapper(ctx, ed, "NewParams", params)
+	erw.fillStackTrace(1, ed.stackTraceDepth())
+	return erw
+}
+
+// newTemplateErrorWrapper creates errorWrapper using the message template of
+// the error definition, method is the calling method reported when the
+// message template is not set
+func newTemplateErrorWrapper(ctx context.Context, ed *ErrorDefinition, method string, params Params) *errorWrapper {
+	if ed.template == nil {
+		panic(&MissingMessageError{Definition: ed, Method: method, Setter: "MessageTemplate"})
+	}
+
+	erw := newErrorWrapper(ctx, ed, ed.template.raw)
+	erw.template = ed.template
+	erw.params = params
+	return erw
+}
+
+func (e *errorWrapper) Params() Params {
+	if e.params == nil {
+		return nil
+	}
+	return Params(e.redactor().data(ErrorData(e.params)))
+}
+
+// renderMessage renders the raw message using the message template and params,
+// or fmt.Sprintf and args if the error wrapper doesn't use message template
+func (e *errorWrapper) renderMessage(args []interface{}, params Params) string {
+	if e.template != nil {
+		return e.template.render(params)
+	}
+	return fmt.Sprintf(e.message, args...)
+}
+
+// unredactedParams returns the params with secret values unwrapped
+func unredactedParams(params Params) Params {
+	var unredacted Params
+	for k, v := range params {
+		s, ok := v.(secret)
+		if !ok {
+			continue
+		}
+		if unredacted == nil {
+			unredacted = make(Params, len(params))
+			for k, v := range params {
+				unredacted[k] = v
+			}
+		}
+		unredacted[k] = s.value
+	}
+	if unredacted == nil {
+		return params
+	}
+	return unredacted
+}
@@ -0,0 +1,142 @@
+package errwrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_parseTemplate(t *testing.T) {
+	tests := []struct {
+		name             string
+		template         string
+		params           Params
+		want             string
+		wantPlaceholders []string
+	}{
+		{
+			name:             "success",
+			template:         "User {user_id} not found in {shop.name}",
+			params:           Params{"user_id": 1, "shop.name": "foo"},
+			want:             "User 1 not found in foo",
+			wantPlaceholders: []string{"user_id", "shop.name"},
+		},
+		{
+			name:             "success escaped braces",
+			template:         "Invalid {{json}} body {reason}}}",
+			params:           Params{"reason": "foo"},
+			want:             "Invalid {json} body foo}",
+			wantPlaceholders: []string{"reason"},
+		},
+		{
+			name:     "success invalid placeholder",
+			template: "Invalid {} body { reason } {unclosed",
+			want:     "Invalid {} body { reason } {unclosed",
+		},
+		{
+			name:             "success missing param",
+			template:         "User {user_id} not found",
+			want:             "User %!{user_id}(MISSING) not found",
+			wantPlaceholders: []string{"user_id"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseTemplate(tt.template)
+			if rendered := got.render(tt.params); rendered != tt.want {
+				t.Errorf("messageTemplate.render() = %v, want %v", rendered, tt.want)
+			}
+			if placeholders := got.placeholders(); !reflect.DeepEqual(placeholders, tt.wantPlaceholders) {
+				t.Errorf("messageTemplate.placeholders() = %v, want %v", placeholders, tt.wantPlaceholders)
+			}
+		})
+	}
+}
+
+func TestErrorDefinition_NewParams(t *testing.T) {
+	config := DefaultConfig()
+	config.RedactKeys = []string{"token"}
+	config.JSONView = JSONViewDebug
+	f := NewFactory(config)
+	ed := f.NewError(100, "ErrUserNotFound", ErrorCategory(1)).MessageTemplate("User {user_id} not found using {token}")
+
+	erw := ed.NewParams(context.Background(), Params{"user_id": 1, "token": "abc"})
+
+	if got, want := erw.Error(), "User 1 not found using [REDACTED] (100)"; got != want {
+		t.Errorf("Error() = %v, want %v", got, want)
+	}
+	if got, want := erw.UnredactedError(), "User 1 not found using abc (100)"; got != want {
+		t.Errorf("UnredactedError() = %v, want %v", got, want)
+	}
+	if got, want := erw.RawMessage(), "User {user_id} not found using {token}"; got != want {
+		t.Errorf("RawMessage() = %v, want %v", got, want)
+	}
+	if got, want := erw.Params(), (Params{"user_id": 1, "token": "[REDACTED]"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Params() = %v, want %v", got, want)
+	}
+	if erw.Args() != nil {
+		t.Errorf("Args() = %v, want nil", erw.Args())
+	}
+	if !strings.Contains(fmt.Sprintf("%+v", erw), "\nparams: map[token:[REDACTED] user_id:1]") {
+		t.Errorf("%%+v = %+v", erw)
+	}
+
+	data, err := json.Marshal(erw)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	restored, err := f.UnmarshalError(data, JSONViewDebug)
+	if err != nil {
+		t.Fatalf("Factory.UnmarshalError() error = %v", err)
+	}
+	if restored.ActualError() != erw.ActualError() || !reflect.DeepEqual(restored.Params(), Params{"user_id": float64(1), "token": "[REDACTED]"}) {
+		t.Errorf("Factory.UnmarshalError() = %v, params %v", restored.ActualError(), restored.Params())
+	}
+
+	converted := Convert(context.Background(), erw, f.NewError(101, "ErrNotFound", ErrorCategory(1)))
+	if got, want := converted.UnredactedError(), "User 1 not found using abc (101)"; got != want {
+		t.Errorf("Convert() UnredactedError() = %v, want %v", got, want)
+	}
+}
+
+func TestErrorDefinition_NewParamsWithoutContext(t *testing.T) {
+	ed := NewFactory(DefaultConfig()).NewError(100, "ErrUserNotFound", ErrorCategory(1)).MessageTemplate("User {user_id} not found")
+
+	erw := ed.NewParamsWithoutContext(Params{"user_id": 1})
+	if got, want := erw.ActualError(), "User 1 not found (100)"; got != want {
+		t.Errorf("ActualError() = %v, want %v", got, want)
+	}
+}
+
+func TestErrorDefinition_NewParams_withoutTemplate(t *testing.T) {
+	ed := NewFactory(DefaultConfig()).NewError(100, "ErrUserNotFound", ErrorCategory(1))
+
+	defer func() {
+		err, ok := recover().(*MissingMessageError)
+		if !ok || err.Definition != ed {
+			t.Fatalf("NewParams() without template panic = %v, want *MissingMessageError", err)
+		}
+		if got, want := err.Error(), "errwrap: NewParams called on ErrUserNotFound without MessageTemplate"; got != want {
+			t.Errorf("MissingMessageError.Error() = %v, want %v", got, want)
+		}
+	}()
+	ed.NewParams(context.Background(), Params{"user_id": 1})
+	t.Errorf("NewParams() without template doesn't panic")
+}
+
+func TestErrorWrapper_LocalizedError_params(t *testing.T) {
+	catalog := NewCatalog()
+	catalog.Add("id", "ErrUserNotFound", "Pengguna {user_id} tidak ditemukan")
+
+	config := DefaultConfig()
+	config.Catalog = catalog
+	ed := NewFactory(config).NewError(100, "ErrUserNotFound", ErrorCategory(1)).MessageTemplate("User {user_id} not found")
+
+	erw := ed.NewParams(InjectLocale(context.Background(), "id"), Params{"user_id": 1})
+	if got, want := erw.LocalizedError(context.Background()), "Pengguna 1 tidak ditemukan (100)"; got != want {
+		t.Errorf("LocalizedError() = %v, want %v", got, want)
+	}
+}
@@ -2,9 +2,7 @@ package errwrap
 
 import (
 	"context"
-	"fmt"
-	"runtime"
-	"strings"
+	"errors"
 )
 
 // ErrorWrapper contains functions to define a wrapped error
@@ -30,37 +28,106 @@ type ErrorWrapper interface {
 	// formatter
 	RawMaskMessage() string
 
-	// Args is error arguments to build error message
+	// Args is error arguments to build error message, with secret and
+	// Redactable arguments redacted
 	Args() []interface{}
 
-	// StackTrace is stack trace where the error is created
+	// Params is named parameters to fill the message template, with the same
+	// redaction as Data. Params is nil if the error is not created from message
+	// template.
+	Params() Params
+
+	// StackTrace is stack trace where the error is created, formatted using
+	// the stack trace mode at the time the error is created
 	StackTrace() []string
 
-	// Data is additinal error data for further debugging
+	// Frames is stack trace frames where the error is created, the returned
+	// slice is a copy and can be modified by the caller
+	Frames() []Frame
+
+	// Data is additinal error data for further debugging, with values of keys
+	// matching Config.RedactKeys and Redactable values redacted
 	Data() ErrorData
 
-	// Is checks if errorWrapper is equals to ErrorDefinition
-	Is(ed *ErrorDefinition) bool
+	// Fields is field violations of the request, safe to be sent to the client
+	Fields() []FieldViolation
 
-	// ActualError returns error message but bypassing mask message
+	// Is checks if errorWrapper is equals to ErrorDefinition. The signature
+	// follows the standard library, so errors.Is can be used to compare any
+	// error with an *ErrorDefinition
+	Is(err error) bool
+
+	// ActualError returns error message but bypassing mask message. Secret and
+	// Redactable arguments are redacted.
 	ActualError() string
+
+	// LocalizedError returns Error() localized to the locale injected into
+	// context, or the locale captured when the error is created if there is
+	// none, using Config.Catalog. Falls back to Error() if there is no
+	// translation.
+	LocalizedError(ctx context.Context) string
+
+	// UnredactedError returns error message bypassing mask message and
+	// redaction, it must not be logged or sent to the client
+	UnredactedError() string
+
+	// Unwrap returns the underlying cause error, or nil if the error doesn't
+	// wrap any error
+	Unwrap() error
 }
 
-// Cast asserts error interface type to ErrorWrapper interface. If the error
-// doesn't implement ErrorWrapper interface, returns nil.
+// Cast finds the first error in err's chain that implements ErrorWrapper
+// interface. If there is no such error, returns nil.
 func Cast(err error) ErrorWrapper {
-	if erw, ok := err.(ErrorWrapper); ok {
+	if erw, ok := As(err); ok {
 		return erw
 	}
 	return nil
 }
 
+// As finds the first error in err's chain that implements ErrorWrapper
+// interface, walking the chain using errors.As. *MultiError found first in the
+// chain is returned as MultiError.ErrorWrapper, instead of its first error
+// wrapper. Returns false if there is no such error.
+func As(err error) (ErrorWrapper, bool) {
+	var coded interface {
+		Code() int
+		CodeString() string
+		Category() ErrorCategory
+	}
+	if errors.As(err, &coded) {
+		if m, ok := coded.(*MultiError); ok && m.Len() > 0 {
+			return m.ErrorWrapper(), true
+		}
+	}
+
+	var erw ErrorWrapper
+	if !errors.As(err, &erw) {
+		return nil, false
+	}
+	return erw, true
+}
+
 // Convert converts an ErrorWrapper into new ErrorWrapper based on
-// *ErrorDefinition
+// *ErrorDefinition. The converted ErrorWrapper is kept as the cause of the new
+// ErrorWrapper.
 func Convert(ctx context.Context, err ErrorWrapper, ed *ErrorDefinition) ErrorWrapper {
-	ctx = InjectErrorData(ctx, err.Data())
-	newErw := newErrorWrapper(ctx, ed, err.RawMessage(), err.Args()...)
-	newErw.fillStackTrace(1)
+	data, args := err.Data(), err.Args()
+	var template *messageTemplate
+	var params Params
+	if erw, ok := err.(*errorWrapper); ok {
+		// keep the unredacted data, args, and params, they are redacted when
+		// rendered
+		data, args = erw.data, erw.args
+		template, params = erw.template, erw.params
+	}
+
+	ctx = InjectErrorData(ctx, data)
+	newErw := newErrorWrapper(ctx, ed, err.RawMessage(), args...)
+	newErw.template = template
+	newErw.params = params
+	newErw.cause = err
+	newErw.fillStackTrace(1, ed.stackTraceDepth())
 	return newErw
 }
 
@@ -71,6 +138,7 @@ type errorWrapper struct {
 	codeString string // error code in string
 
 	message   string           // error message
+	restored  *string          // actual error message rendered by another service
 	category  ErrorCategory    // error category
 	formatter MessageFormatter // message formatter function
 
@@ -79,15 +147,26 @@ type errorWrapper struct {
 	maskFormatter MaskFormatter // mask formatter function
 
 	args       []interface{}
-	stackTrace []string
+	template   *messageTemplate // message template, nil if message is fmt format
+	params     Params           // message template params
+	stack      *stack           // captured stack, resolved when stack trace is read
+	stackTrace []string         // resolved stack trace, used when stack is nil
 	data       ErrorData
+	dataLayers *errorDataWrapper // injected error data layers
+	fields     []FieldViolation  // field violations
+	locale     string            // locale of the context creating the error
+	cause      error             // underlying cause error
+	factory    *Factory          // factory which creates the error definition
 }
 
 // newErrorWrapper creates errorWrapper based on error definition
 func newErrorWrapper(ctx context.Context, ed *ErrorDefinition, rawMessage string, args ...interface{}) *errorWrapper {
-	msgformatter := DefaultMessageFormatter
-	maskMessage := DefaultMaskMessage
-	maskFormatter := DefaultMaskFormatter
+	factory := ed.getFactory()
+	config := factory.getConfig()
+
+	msgformatter := config.MessageFormatter
+	maskMessage := config.MaskMessage
+	maskFormatter := config.MaskFormatter
 	if ed.formatter != nil {
 		msgformatter = *ed.formatter
 	}
@@ -98,6 +177,8 @@ func newErrorWrapper(ctx context.Context, ed *ErrorDefinition, rawMessage string
 		maskFormatter = *ed.maskFormatter
 	}
 
+	layers := getErrorDataWrapper(ctx)
+
 	erw := &errorWrapper{
 		code:       ed.code,
 		codeString: ed.codeString,
@@ -110,8 +191,11 @@ func newErrorWrapper(ctx context.Context, ed *ErrorDefinition, rawMessage string
 		maskMessage:   maskMessage,
 		maskFormatter: maskFormatter,
 
-		args: args,
-		data: getErrorData(ctx),
+		args:       args,
+		data:       layers.merge(config.DataMergePolicies),
+		dataLayers: layers,
+		locale:     LocaleFromContext(ctx),
+		factory:    factory,
 	}
 	return erw
 }
@@ -141,20 +225,74 @@ func (e *errorWrapper) RawMaskMessage() string {
 }
 
 func (e *errorWrapper) Args() []interface{} {
-	return e.args
+	return e.redactor().args(e.args)
 }
 
 func (e *errorWrapper) StackTrace() []string {
+	if e.stack != nil {
+		return e.stack.lines()
+	}
 	return e.stackTrace
 }
 
+func (e *errorWrapper) Frames() []Frame {
+	if e.stack == nil {
+		return nil
+	}
+
+	// copy the cached frames, so the caller can't modify them for other readers
+	frames := e.stack.resolve()
+	copied := make([]Frame, len(frames))
+	copy(copied, frames)
+	return copied
+}
+
 func (e *errorWrapper) Data() ErrorData {
-	return e.data
+	return e.redactor().data(e.data)
+}
+
+func (e *errorWrapper) Fields() []FieldViolation {
+	return e.fields
+}
+
+// DataLayers returns the error data layers of the first ErrorWrapper in the
+// error chain, from the last injected layer. The layers are only available
+// for error wrappers created by ErrorDefinition, and the values are redacted.
+func DataLayers(err error) []ErrorDataLayer {
+	erw, ok := As(err)
+	if !ok {
+		return nil
+	}
+	e, ok := erw.(*errorWrapper)
+	if !ok {
+		return nil
+	}
+
+	layers := e.dataLayers.layers()
+	r := e.redactor()
+	for i := range layers {
+		layers[i].Data = r.data(layers[i].Data)
+	}
+	return layers
+}
+
+func (e *errorWrapper) Unwrap() error {
+	return e.cause
+}
+
+// getFactory returns the factory which creates the error definition of this
+// error wrapper, or DefaultFactory if the error wrapper is not created by a
+// factory
+func (e *errorWrapper) getFactory() *Factory {
+	if e.factory != nil {
+		return e.factory
+	}
+	return DefaultFactory
 }
 
 func (e *errorWrapper) Error() string {
 	if e.isMasked {
-		fn := DefaultMaskFormatter
+		fn := defaultMaskFormatter
 		if e.maskFormatter != nil {
 			fn = e.maskFormatter
 		}
@@ -164,54 +302,53 @@ func (e *errorWrapper) Error() string {
 	return e.ActualError()
 }
 
-func (e *errorWrapper) Is(ed *ErrorDefinition) bool {
-	if e == nil || ed == nil {
+func (e *errorWrapper) Is(err error) bool {
+	ed, ok := err.(*ErrorDefinition)
+	if e == nil || !ok || ed == nil {
 		return false
 	}
 	return e.code == ed.code
 }
 
 func (e *errorWrapper) ActualError() string {
-	return e.formatErrorMessage(fmt.Sprintf(e.message, e.args...))
-}
-
-// formatErrorMessage formats message using formatter function
-func (e *errorWrapper) formatErrorMessage(msg string) string {
-	fn := DefaultMessageFormatter
-	if e.formatter != nil {
-		fn = e.formatter
+	if e.restored != nil {
+		return *e.restored
 	}
-
-	return fn(msg, e)
+	return e.formatErrorMessage(e.renderMessage(e.redactor().renderArgs(e.args), e.Params()))
 }
 
-// fillStackTrace fills errorWrapper stack trace
-func (e *errorWrapper) fillStackTrace(offset int) {
-	lines := make([]string, 0)
-
-	for i := 1 + offset; ; i++ {
-		// https://lawlessguy.wordpress.com/2016/04/17/display-file-function-and-line-number-in-go-golang/
-		fnptr, file, line, ok := runtime.Caller(i)
-		if !ok {
-			break
-		}
+func (e *errorWrapper) LocalizedError(ctx context.Context) string {
+	locale := LocaleFromContext(ctx)
+	if locale == "" {
+		locale = e.locale
+	}
 
-		funcName := runtime.FuncForPC(fnptr).Name()
-		if DefaultPackagePrefix != "" && !strings.HasPrefix(funcName, DefaultPackagePrefix) {
-			break
+	if catalog := e.getFactory().getConfig().Catalog; catalog != nil && locale != "" {
+		if msg, ok := catalog.localize(e, locale); ok {
+			return msg
 		}
+	}
+	return e.Error()
+}
 
-		switch DefaultStackTraceMode {
-		case StackTraceModeFull:
-			lines = append(lines, fmt.Sprintf("%s:%d (%s)", file, line, funcName))
+func (e *errorWrapper) UnredactedError() string {
+	if e.restored != nil {
+		return *e.restored
+	}
+	return e.formatErrorMessage(e.renderMessage(unredactedArgs(e.args), unredactedParams(e.params)))
+}
 
-		case StackTraceModeLineOnly:
-			lines = append(lines, fmt.Sprintf("%s:%d", file, line))
+// redactor returns the redactor using the factory settings
+func (e *errorWrapper) redactor() redactor {
+	return newRedactor(e.getFactory().getConfig())
+}
 
-		case StackTraceModeFuncOnly:
-			lines = append(lines, fmt.Sprintf("%s", funcName))
-		}
+// formatErrorMessage formats message using formatter function
+func (e *errorWrapper) formatErrorMessage(msg string) string {
+	fn := defaultMessageFormatter
+	if e.formatter != nil {
+		fn = e.formatter
 	}
 
-	e.stackTrace = lines
+	return fn(msg, e)
 }
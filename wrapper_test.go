@@ -3,6 +3,7 @@ package errwrap
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
 	"testing"
 )
@@ -33,6 +34,23 @@ func TestCast(t *testing.T) {
 				category:   ErrorCategory(1),
 			},
 		},
+		{
+			name: "success wrapped",
+			args: args{
+				err: fmt.Errorf("wrapped: %w", &errorWrapper{
+					code:       100,
+					codeString: "ErrTest",
+					message:    "Test error message: %s",
+					category:   ErrorCategory(1),
+				}),
+			},
+			want: &errorWrapper{
+				code:       100,
+				codeString: "ErrTest",
+				message:    "Test error message: %s",
+				category:   ErrorCategory(1),
+			},
+		},
 		{
 			name: "success error not implemented ErrorWrapper",
 			args: args{
@@ -50,6 +68,78 @@ func TestCast(t *testing.T) {
 	}
 }
 
+func TestAs(t *testing.T) {
+	type args struct {
+		err error
+	}
+	tests := []struct {
+		name   string
+		args   args
+		want   ErrorWrapper
+		wantOk bool
+	}{
+		{
+			name: "success",
+			args: args{
+				err: &errorWrapper{
+					code:       100,
+					codeString: "ErrTest",
+					category:   ErrorCategory(1),
+				},
+			},
+			want: &errorWrapper{
+				code:       100,
+				codeString: "ErrTest",
+				category:   ErrorCategory(1),
+			},
+			wantOk: true,
+		},
+		{
+			name: "success wrapped multiple times",
+			args: args{
+				err: fmt.Errorf("outer: %w", fmt.Errorf("inner: %w", &errorWrapper{
+					code:       100,
+					codeString: "ErrTest",
+					category:   ErrorCategory(1),
+				})),
+			},
+			want: &errorWrapper{
+				code:       100,
+				codeString: "ErrTest",
+				category:   ErrorCategory(1),
+			},
+			wantOk: true,
+		},
+		{
+			name: "success error not implemented ErrorWrapper",
+			args: args{
+				err: fmt.Errorf("outer: %w", errors.New("an error")),
+			},
+			want:   nil,
+			wantOk: false,
+		},
+		{
+			name: "success error is nil",
+			args: args{
+				err: nil,
+			},
+			want:   nil,
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotOk := As(tt.args.err)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("As() got = %v, want %v", got, tt.want)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("As() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
 func TestConvert(t *testing.T) {
 	type args struct {
 		ctx context.Context
@@ -91,10 +181,20 @@ func TestConvert(t *testing.T) {
 					"foo": "bar",
 					"bar": "baz",
 				},
+				cause: &errorWrapper{
+					code:       100,
+					codeString: "ErrTest",
+					message:    "Test error message",
+					category:   ErrorCategory(1),
+					data: ErrorData{
+						"foo": "bar",
+					},
+				},
 
 				formatter:     nil,
 				maskMessage:   DefaultMaskMessage,
 				maskFormatter: nil,
+				factory:       DefaultFactory,
 			},
 		},
 	}
@@ -102,7 +202,9 @@ func TestConvert(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			got := Convert(tt.args.ctx, tt.args.err, tt.args.ed)
 			if g, ok := got.(*errorWrapper); ok {
+				g.stack = nil
 				g.stackTrace = nil
+				g.dataLayers = nil
 				g.formatter = nil
 				g.maskFormatter = nil
 			}
@@ -153,6 +255,7 @@ func Test_newErrorWrapper(t *testing.T) {
 				formatter:     nil,
 				maskMessage:   DefaultMaskMessage,
 				maskFormatter: nil,
+				factory:       DefaultFactory,
 			},
 		},
 	}
@@ -162,6 +265,7 @@ func Test_newErrorWrapper(t *testing.T) {
 
 			got.formatter = nil
 			got.maskFormatter = nil
+			got.dataLayers = nil
 
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("newErrorWrapper() = %v, want %v", got, tt.want)
@@ -860,6 +964,56 @@ func Test_errorWrapper_Is(t *testing.T) {
 	}
 }
 
+func Test_errorWrapper_Is_errorsIs(t *testing.T) {
+	edTest := NewError(100, "ErrTest", ErrorCategory(1))
+	edTestNew := NewError(101, "ErrTestNew", ErrorCategory(2))
+
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{
+			name:   "success equal",
+			err:    edTest.NewWithoutContext("Test error message"),
+			target: edTest,
+			want:   true,
+		},
+		{
+			name:   "success equal wrapped by fmt.Errorf",
+			err:    fmt.Errorf("wrapped: %w", edTest.NewWithoutContext("Test error message")),
+			target: edTest,
+			want:   true,
+		},
+		{
+			name:   "success equal converted",
+			err:    Convert(context.Background(), edTest.NewWithoutContext("Test error message"), edTestNew),
+			target: edTest,
+			want:   true,
+		},
+		{
+			name:   "success not equal",
+			err:    fmt.Errorf("wrapped: %w", edTest.NewWithoutContext("Test error message")),
+			target: edTestNew,
+			want:   false,
+		},
+		{
+			name:   "success target is not error definition",
+			err:    edTest.NewWithoutContext("Test error message"),
+			target: errors.New("an error"),
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.target); got != tt.want {
+				t.Errorf("errors.Is() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_errorWrapper_ActualError(t *testing.T) {
 	type fields struct {
 		code          int
@@ -916,3 +1070,84 @@ func Test_errorWrapper_ActualError(t *testing.T) {
 		})
 	}
 }
+
+func Test_errorWrapper_Unwrap(t *testing.T) {
+	cause := errors.New("an error")
+
+	type fields struct {
+		code       int
+		codeString string
+		message    string
+		category   ErrorCategory
+		cause      error
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   error
+	}{
+		{
+			name: "success",
+			fields: fields{
+				code:       100,
+				codeString: "ErrTest",
+				message:    "Test error message",
+				category:   ErrorCategory(1),
+				cause:      cause,
+			},
+			want: cause,
+		},
+		{
+			name: "success no cause",
+			fields: fields{
+				code:       100,
+				codeString: "ErrTest",
+				message:    "Test error message",
+				category:   ErrorCategory(1),
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &errorWrapper{
+				code:       tt.fields.code,
+				codeString: tt.fields.codeString,
+				message:    tt.fields.message,
+				category:   tt.fields.category,
+				cause:      tt.fields.cause,
+			}
+			if got := e.Unwrap(); got != tt.want {
+				t.Errorf("errorWrapper.Unwrap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDataLayers(t *testing.T) {
+	config := DefaultConfig()
+	config.DataMergePolicies = map[string]MergePolicy{"id": MergeCollect}
+	config.RedactKeys = []string{"token"}
+	ed := NewFactory(config).NewError(100, "ErrTest", ErrorCategory(1))
+
+	ctx := InjectErrorData(context.Background(), ErrorData{"id": "order-1", "token": "abc"})
+	ctx = InjectErrorData(ctx, ErrorData{"id": "item-1"})
+	err := fmt.Errorf("handler: %w", ed.New(ctx, "Test error message"))
+
+	wantLayers := []ErrorDataLayer{
+		{Data: ErrorData{"id": "item-1"}},
+		{Data: ErrorData{"id": "order-1", "token": DefaultRedactPlaceholder}},
+	}
+	if got := DataLayers(err); !reflect.DeepEqual(got, wantLayers) {
+		t.Errorf("DataLayers() = %v, want %v", got, wantLayers)
+	}
+
+	wantData := ErrorData{"id": []interface{}{"order-1", "item-1"}, "token": DefaultRedactPlaceholder}
+	if got := Cast(err).Data(); !reflect.DeepEqual(got, wantData) {
+		t.Errorf("Data() = %v, want %v", got, wantData)
+	}
+
+	if got := DataLayers(errors.New("not error wrapper")); got != nil {
+		t.Errorf("DataLayers() = %v, want nil", got)
+	}
+}